@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Audit repositories of release branches",
+	}
+
+	tagsCmd = &cobra.Command{
+		Use:   "tags",
+		Short: "Audit whether tags of repositories are aligned on each release branch",
+		Long: `Audit whether tags of repositories are aligned on each release branch
+
+The matrix of versions against repositories is printed, the cell is
+  <tag>  - the version is tagged
+  !<tag> - the tag has issues
+  -      - the version is not tagged
+The following issues are reported, and exit with non-zero code if any
+  missing      - version between the oldest and latest tags of repo is not tagged
+  skew         - latest version of repo is behind the release
+  wrong-branch - tag is not reachable from the release branch
+  unreachable  - tag is not reachable from any branch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return auditTags(configFile)
+		},
+	}
+)
+
+var (
+	configFile   string
+	noFetch      bool
+	outputFormat string
+)
+
+func init() {
+	tagsCmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file (required)")
+	tagsCmd.MarkFlagRequired("config")
+	tagsCmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch each repository")
+	tagsCmd.Flags().StringVarP(&outputFormat, "output-format", "o", "table", "Output format, choices(`table|json`)")
+
+	Cmd.AddCommand(tagsCmd)
+}
+
+func auditTags(configFile string) error {
+	config, err := common.LoadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	if err := common.InitLocalRepos(config, noFetch); err != nil {
+		return errors.Wrap(err, "init local repository")
+	}
+
+	gen := changelog.NewGlobalGenerator(config)
+	result, err := gen.AuditTags()
+	if err != nil {
+		return errors.Wrap(err, "audit tags")
+	}
+
+	if err := printTagAudit(os.Stdout, result, outputFormat); err != nil {
+		return err
+	}
+
+	if result.HasIssues() {
+		return errors.Errorf("tags of repositories are not aligned")
+	}
+	return nil
+}
+
+func printTagAudit(w io.Writer, result *types.GlobalTagAudit, outputFormat string) error {
+	switch outputFormat {
+	case "json":
+		fmt.Fprintln(w, jsonutils.Marshal(result).PrettyString())
+		return nil
+	case "table":
+		for _, rls := range result.Releases {
+			if err := printReleaseTagAudit(w, rls); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("Not support output format: %q", outputFormat)
+}
+
+func printReleaseTagAudit(w io.Writer, rls *types.ReleaseTagAudit) error {
+	fmt.Fprintf(w, "%s\n\n", rls.Branch)
+
+	issueTags := make(map[string]bool)
+	for _, issue := range rls.Issues {
+		issueTags[issue.Repo+"/"+issue.Version] = true
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := []string{"VERSION"}
+	for _, repo := range rls.Repos {
+		header = append(header, repo.Repo)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, version := range rls.Versions {
+		row := []string{version}
+		for _, repo := range rls.Repos {
+			cell := "-"
+			if tag := repo.Tag(version); tag != nil {
+				cell = tag.Name
+				if issueTags[repo.Repo+"/"+version] {
+					cell = "!" + cell
+				}
+			}
+			row = append(row, cell)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(rls.Issues) == 0 {
+		fmt.Fprintf(w, "\nno issues\n\n")
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tVERSION\tISSUE\tMESSAGE")
+	for _, issue := range rls.Issues {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", issue.Repo, issue.Version, issue.Kind, issue.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	return nil
+}
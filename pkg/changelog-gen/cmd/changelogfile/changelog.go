@@ -0,0 +1,86 @@
+package changelogfile
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/log"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "changelog",
+		Short: "CHANGELOG file of repository related actions",
+	}
+
+	updateCmd = &cobra.Command{
+		Use:   "update",
+		Short: "Insert new versions and replace unreleased section of CHANGELOG file, the other sections are kept",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return update()
+		},
+	}
+)
+
+var (
+	configFile    string
+	repoDir       string
+	repoURL       string
+	fileName      string
+	templateFile  string
+	query         string
+	commit        bool
+	commitMessage string
+)
+
+func init() {
+	updateCmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file to read options, default options are used if not set")
+	updateCmd.Flags().StringVarP(&repoDir, "repo", "r", ".", "Repository directory")
+	updateCmd.Flags().StringVar(&repoURL, "repo-url", "", "Repository URL used for links, default is the url of 'origin' remote")
+	updateCmd.Flags().StringVarP(&fileName, "file", "f", "CHANGELOG.md", "CHANGELOG file path relative to repository directory")
+	updateCmd.Flags().StringVarP(&templateFile, "template", "t", "./template/CHANGELOG.repo.tpl.md", "Template file, each version section must start with '<a name=\"TAG\"></a>' line")
+	updateCmd.Flags().StringVarP(&query, "query", "q", "", "Tag query, default is from the latest version of CHANGELOG file (e.g. 'v1.0.0..')")
+	updateCmd.Flags().BoolVar(&commit, "commit", false, "Commit CHANGELOG file, the author is read from 'user.name' and 'user.email' of git config")
+	updateCmd.Flags().StringVarP(&commitMessage, "message", "m", "docs(changelog): update CHANGELOG", "Commit message")
+
+	Cmd.AddCommand(updateCmd)
+}
+
+func update() error {
+	opts, err := common.LoadOptions(configFile)
+	if err != nil {
+		return err
+	}
+
+	repo, workingDir, err := common.OpenRepository(repoDir, "", "", true)
+	if err != nil {
+		return err
+	}
+
+	gen, err := common.NewRepoGenerator(repo, workingDir, repoURL, templateFile, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	outFile := filepath.Join(repoDir, fileName)
+	if err := gen.UpdateFile(outFile, query); err != nil {
+		return errors.Wrapf(err, "update %q", outFile)
+	}
+	log.Infof("%q updated", outFile)
+
+	if !commit {
+		return nil
+	}
+
+	hash, err := repo.CommitFiles(commitMessage, filepath.ToSlash(filepath.Clean(fileName)))
+	if err != nil {
+		return errors.Wrapf(err, "commit %q", fileName)
+	}
+	log.Infof("%q committed as %s", outFile, hash)
+
+	return nil
+}
@@ -3,8 +3,14 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/audit"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/changelogfile"
 	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/config"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/generate"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/lint"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/nextversion"
 	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/run"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/stats"
 )
 
 var (
@@ -15,8 +21,14 @@ var (
 )
 
 func init() {
+	rootCmd.AddCommand(audit.Cmd)
+	rootCmd.AddCommand(changelogfile.Cmd)
 	rootCmd.AddCommand(config.Cmd)
+	rootCmd.AddCommand(generate.Cmd)
+	rootCmd.AddCommand(lint.Cmd)
+	rootCmd.AddCommand(nextversion.Cmd)
 	rootCmd.AddCommand(run.Cmd)
+	rootCmd.AddCommand(stats.Cmd)
 }
 
 func Execute() error {
@@ -0,0 +1,252 @@
+package common
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/log"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/gitlib"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+// LoadConfig reads the yaml config file and converts it to normalized internal config
+func LoadConfig(configFile string) (*types.GlobalChangeLogConfig, error) {
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read config file %q", configFile)
+	}
+
+	jObj, err := jsonutils.ParseYAML(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse config %s yaml content", configFile)
+	}
+
+	configV1 := new(types.GlobalChangeLogConfigV1)
+	if err := jObj.Unmarshal(configV1); err != nil {
+		return nil, errors.Wrap(err, "load config")
+	}
+	config, err := configV1.ToInternalConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "config to internal config")
+	}
+	NormalizeConfig(config)
+
+	if err := validateFilters(config); err != nil {
+		return nil, errors.Wrap(err, "validate commit filters")
+	}
+
+	if config.Options.OverridesFile != "" {
+		config.Overrides, err = gitlib.LoadCommitOverrides(config.Options.OverridesFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load commit overrides")
+		}
+	}
+
+	return config, nil
+}
+
+// validateFilters compiles the commit filters of options, repos and output
+func validateFilters(config *types.GlobalChangeLogConfig) error {
+	filters := map[string]*types.CommitFilterConfig{
+		"options": config.Options.Filter,
+		"output":  config.Output.Filter,
+	}
+	for name, filter := range config.RepoFilters {
+		filters["repo "+name] = filter
+	}
+
+	for name, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		if _, err := gitlib.NewCommitExprFilter(filter); err != nil {
+			return errors.Wrapf(err, "filter of %s", name)
+		}
+	}
+	return nil
+}
+
+// LoadOptions reads `options` of the yaml config file, the default options are used if configFile is empty
+func LoadOptions(configFile string) (*types.ChangelogConfigOptions, error) {
+	opts := new(types.ChangelogConfigOptions)
+
+	if configFile != "" {
+		content, err := ioutil.ReadFile(configFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read config file %q", configFile)
+		}
+
+		jObj, err := jsonutils.ParseYAML(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse config %s yaml content", configFile)
+		}
+
+		configV1 := new(types.GlobalChangeLogConfigV1)
+		if err := jObj.Unmarshal(configV1); err != nil {
+			return nil, errors.Wrap(err, "load config")
+		}
+		if configV1.Options != nil {
+			opts = configV1.Options
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "options config")
+	}
+	if opts.Filter != nil {
+		if _, err := gitlib.NewCommitExprFilter(opts.Filter); err != nil {
+			return nil, errors.Wrap(err, "filter of options")
+		}
+	}
+
+	NormalizeOptions(opts)
+	return opts, nil
+}
+
+// InitLocalRepos clones or opens each repository into cache dir, then fetches it and the notes ref unless `noFetch`.
+// The discovered release branches are appended to releases of config
+func InitLocalRepos(config *types.GlobalChangeLogConfig, noFetch bool) error {
+	for _, rls := range config.Releases {
+		for _, repo := range rls.Repos {
+			if _, err := initLocalRepo(config.CacheDir, repo, config.Options.NotesRef, noFetch); err != nil {
+				return err
+			}
+		}
+	}
+
+	if config.Discovery != nil {
+		if err := discoverReleases(config, noFetch); err != nil {
+			return errors.Wrap(err, "discover releases")
+		}
+	}
+
+	return nil
+}
+
+func initLocalRepo(cacheDir string, repo *types.Repository, notesRef string, noFetch bool) (*gitlib.Repository, error) {
+	// set repo default name
+	repo.URL = strings.TrimRight(repo.URL, "/")
+	urlSegs := strings.Split(repo.URL, "/")
+	if len(urlSegs) == 0 {
+		return nil, errors.Errorf("Invalid repo url %q", repo.URL)
+	}
+	if repo.Name == "" {
+		repo.Name = urlSegs[len(urlSegs)-1]
+	}
+	if repo.WorkingDir == "" {
+		repo.WorkingDir = path.Join(cacheDir, repo.Name)
+	}
+
+	repoObj, err := gitlib.NewRepository(repo.WorkingDir, repo.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "newRepository %q", repo.URL)
+	}
+
+	if noFetch {
+		return repoObj, nil
+	}
+	if err := repoObj.Fetch(); err != nil {
+		return nil, errors.Wrapf(err, "fetch repo %s", repoObj.LogPrefix())
+	}
+	if notesRef != "" {
+		if err := repoObj.FetchNotes(notesRef); err != nil {
+			return nil, errors.Wrapf(err, "fetch notes of repo %s", repoObj.LogPrefix())
+		}
+	}
+
+	return repoObj, nil
+}
+
+// discoverReleases lists remote branches of discovery repos, release versions already in config are skipped
+func discoverReleases(config *types.GlobalChangeLogConfig, noFetch bool) error {
+	patterns := config.Options.ReleaseBranchPatterns
+
+	exclude := make(map[string]bool)
+	for _, rls := range config.Releases {
+		verStr, err := changelog.GetSemverBranchVersion(rls.Branch, patterns...)
+		if err != nil {
+			return errors.Wrapf(err, "release %q", rls.Branch)
+		}
+		exclude[verStr] = true
+	}
+
+	repoBranches := make([][]string, len(config.Discovery.Repos))
+	for idx, repo := range config.Discovery.Repos {
+		repoObj, err := initLocalRepo(config.CacheDir, repo, config.Options.NotesRef, noFetch)
+		if err != nil {
+			return err
+		}
+		branches, err := repoObj.RemoteBranches()
+		if err != nil {
+			return errors.Wrapf(err, "list branches of %s", repoObj.LogPrefix())
+		}
+		repoBranches[idx] = branches
+	}
+
+	releases, err := changelog.DiscoverReleases(config.Discovery, patterns, repoBranches, exclude)
+	if err != nil {
+		return err
+	}
+	for _, rls := range releases {
+		log.Infof("discovered release %q of %d repos", rls.Branch, len(rls.Repos))
+	}
+	config.Releases = append(config.Releases, releases...)
+
+	return nil
+}
+
+func NormalizeConfig(config *types.GlobalChangeLogConfig) {
+	if config.Output == nil {
+		config.Output = &types.GlobalChangelogOutConfig{
+			Dir: "./_output/changelog",
+		}
+	}
+
+	if config.Options == nil {
+		config.Options = new(types.ChangelogConfigOptions)
+	}
+
+	NormalizeOptions(config.Options)
+}
+
+// NormalizeOptions sets the default options for yunionio projects
+func NormalizeOptions(opt *types.ChangelogConfigOptions) {
+	opt.UseSemVer = true
+	opt.NoMerges = true
+	if opt.CommitGroupTitleMaps == nil {
+		opt.CommitGroupTitleMaps = make(map[string]string)
+	}
+
+	for key, title := range map[string]string{
+		"feat":     "Features",
+		"fix":      "Bug Fixes",
+		"perf":     "Performance Improvements",
+		"refactor": "Code Refactoring",
+	} {
+		opt.CommitGroupTitleMaps[key] = title
+	}
+
+	if len(opt.HeaderPatternMaps) == 0 {
+		opt.HeaderPatternMaps = []string{"Type", "Scope", "Subject"}
+	}
+	if len(opt.HeaderPattern) == 0 {
+		opt.HeaderPattern = "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$"
+	}
+	if opt.CommitGroupBy == "" {
+		opt.CommitGroupBy = "Type"
+	}
+	if opt.CommitGroupSortBy == "" {
+		opt.CommitGroupSortBy = "Title"
+	}
+	if opt.CommitSortBy == "" {
+		opt.CommitSortBy = "Scope"
+	}
+	if len(opt.NoteKeywords) == 0 {
+		opt.NoteKeywords = []string{"BREAKING CHANGE"}
+	}
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func initOriginRepo(dir string) error {
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("origin"), 0644); err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		return err
+	}
+	_, err = wt.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "foo", Email: "foo@example.com", When: time.Now()},
+	})
+	return err
+}
+
+func TestInitLocalReposNoFetch(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "init-local-repos")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	origin := filepath.Join(dir, "origin")
+	assert.Nil(initOriginRepo(origin))
+
+	config := &types.GlobalChangeLogConfig{
+		CacheDir: filepath.Join(dir, "cache"),
+		Options:  &types.ChangelogConfigOptions{},
+		Releases: []*types.ReleaseChangeLogConfig{
+			{
+				Branch: "release/3.6",
+				Repos: []*types.Repository{
+					{URL: origin, Name: "foo"},
+					{URL: origin, Name: "bar"},
+				},
+			},
+			{
+				Branch: "release/3.7",
+				Repos: []*types.Repository{
+					{URL: origin + "/", Name: "baz"},
+				},
+			},
+		},
+	}
+
+	// every repo is cloned without fetching, not only the first one
+	assert.Nil(InitLocalRepos(config, true))
+	for _, name := range []string{"foo", "bar", "baz"} {
+		_, err := git.PlainOpen(filepath.Join(dir, "cache", name))
+		assert.Nil(err, name)
+	}
+	assert.Equal(filepath.Join(dir, "cache", "baz"), config.Releases[1].Repos[0].WorkingDir)
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"yunion.io/x/log"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/gitlib"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+// IsRemoteURL checks whether repo is remote url rather than local directory
+func IsRemoteURL(repo string) bool {
+	return strings.Contains(repo, "://") || strings.HasPrefix(repo, "git@")
+}
+
+// OpenRepository opens the local repository directory, or clones the remote url into cache dir and fetches it
+// and the notes ref unless `noFetch`. The working directory of repository is returned
+func OpenRepository(repo string, cacheDir string, notesRef string, noFetch bool) (*gitlib.Repository, string, error) {
+	if !IsRemoteURL(repo) {
+		repoObj, err := gitlib.NewRepository(repo, "")
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "open repository %q", repo)
+		}
+		return repoObj, repo, nil
+	}
+
+	url := strings.TrimSuffix(strings.TrimRight(repo, "/"), ".git")
+	workingDir := path.Join(cacheDir, path.Base(url))
+	repoObj, err := gitlib.NewRepository(workingDir, repo)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "newRepository %q", repo)
+	}
+	if !noFetch {
+		if err := repoObj.Fetch(); err != nil {
+			return nil, "", errors.Wrapf(err, "fetch repo %s", repoObj.LogPrefix())
+		}
+		if notesRef != "" {
+			if err := repoObj.FetchNotes(notesRef); err != nil {
+				return nil, "", errors.Wrapf(err, "fetch notes of repo %s", repoObj.LogPrefix())
+			}
+		}
+	}
+	return repoObj, workingDir, nil
+}
+
+// RepoScope restricts commits of a single repository
+type RepoScope struct {
+	// Filters are applied besides `Filter` of options
+	Filters []*types.CommitFilterConfig
+	// Paths are path specs of `git log`
+	Paths      []string
+	Components []*types.ComponentConfig
+}
+
+// ParseComponent parses component of `<name>=<glob>[,<glob>...]` (e.g. `compute=pkg/compute,cmd/region`)
+func ParseComponent(spec string) (*types.ComponentConfig, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("invalid component %q, the format is <name>=<glob>[,<glob>...]", spec)
+	}
+	return &types.ComponentConfig{
+		Name:  parts[0],
+		Paths: strings.Split(parts[1], ","),
+	}, nil
+}
+
+// NewRepoGenerator creates `Generator` of a single repository, the links are added if repoURL is a http url.
+// The url of 'origin' remote is used if repoURL is empty, the commits are restricted by scope if not nil
+func NewRepoGenerator(repo *gitlib.Repository, workingDir string, repoURL string, templateFile string, opts *types.ChangelogConfigOptions, scope *RepoScope) (*changelog.Generator, error) {
+	if repoURL == "" {
+		url, err := repo.GetURL()
+		if err != nil {
+			log.Warningf("get repository url: %v", err)
+		}
+		repoURL = url
+	}
+
+	absTemplate, err := filepath.Abs(templateFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "abs path of %q", templateFile)
+	}
+
+	conf := &types.ChangelogConfig{
+		Bin:        "git",
+		WorkingDir: workingDir,
+		Template:   absTemplate,
+		Info: &types.ChangelogConfigInfo{
+			RepositoryURL: repoURL,
+		},
+		Options: opts,
+	}
+	if scope != nil {
+		conf.Filters = scope.Filters
+		conf.Paths = scope.Paths
+		conf.Components = scope.Components
+	}
+	if opts.OverridesFile != "" {
+		conf.Overrides, err = gitlib.LoadCommitOverrides(opts.OverridesFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load commit overrides")
+		}
+	}
+
+	var processor gitlib.Processor
+	if strings.HasPrefix(repoURL, "http") {
+		processor = &gitlib.GitHubProcessor{}
+	}
+
+	return changelog.NewGenerator(conf, processor), nil
+}
@@ -0,0 +1,141 @@
+package generate
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+	"github.com/yunionio/git-tools/pkg/gitlib"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "generate [query]",
+		Short: "Generate changelog of a single repository",
+		Long: `Generate changelog of a single repository by tag query
+
+The query can be specified with the following rule
+  <old>..<new> - Commit contained in <new> tags from <old> (e.g. v1.0.0..v2.0.0)
+  <tagname>..  - Commit from the <tagname> to the latest tag (e.g. v1.0.0..)
+  ..<tagname>  - Commit from the oldest tag to <tagname> (e.g. ..v1.0.0)
+  <tagname>    - Commit contained in <tagname> (e.g. v1.0.0)
+Endpoints can also be dates, commits or branches
+  since:<date>..until:<date> - Tags created in the dates (e.g. since:2024-01-01..until:2024-06-30)
+  <commit>..<branch>         - Tags reachable from <branch> but not <commit> (e.g. 1a2b3c4..release/3.11)
+All tags are used if query is empty`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			return generate(query)
+		},
+	}
+)
+
+var (
+	configFile        string
+	repo              string
+	repoURL           string
+	cacheDir          string
+	noFetch           bool
+	templateFile      string
+	outputFile        string
+	nextTag           string
+	headerPattern     string
+	headerPatternMaps []string
+	noSemVer          bool
+	tagFilterPattern  string
+	includes          []string
+	excludes          []string
+	paths             []string
+	components        []string
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file to read options, default options are used if not set")
+	Cmd.Flags().StringVarP(&repo, "repo", "r", ".", "Repository directory or remote url")
+	Cmd.Flags().StringVar(&repoURL, "repo-url", "", "Repository URL used for links, default is the url of 'origin' remote")
+	Cmd.Flags().StringVar(&cacheDir, "cache-dir", "./_cache/", "Clone directory of remote repository")
+	Cmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch remote repository")
+	Cmd.Flags().StringVarP(&templateFile, "template", "t", "./template/CHANGELOG.repo.tpl.md", "Template file")
+	Cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file, default is stdout")
+	Cmd.Flags().StringVar(&nextTag, "next-tag", "", "Treat unreleased commits as specified tag")
+	Cmd.Flags().StringVar(&headerPattern, "header-pattern", "", "A regular expression to use for parsing the commit header")
+	Cmd.Flags().StringSliceVar(&headerPatternMaps, "header-pattern-maps", nil, "Commit properties mapped by the groups of --header-pattern (e.g. Type,Scope,Subject)")
+	Cmd.Flags().BoolVar(&noSemVer, "no-semver", false, "Sort tags by date instead of semantic version")
+	Cmd.Flags().StringVar(&tagFilterPattern, "tag-filter-pattern", "", "Filter tags by regexp, used with --no-semver")
+	Cmd.Flags().StringArrayVar(&includes, "include", nil, "Keep commits matched by the filter expression, repeat to keep commits matched by any (e.g. 'type in (feat,fix)')")
+	Cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Drop commits matched by the filter expression (e.g. 'author.email =~ \"bot@\"')")
+	Cmd.Flags().StringArrayVar(&paths, "path", nil, "Only use commits touching the path spec, repeat for multiple paths (e.g. pkg/compute)")
+	Cmd.Flags().StringArrayVar(&components, "component", nil, "Split commits into component by touched files, repeat for multiple components (e.g. 'compute=pkg/compute,cmd/region')")
+}
+
+func generate(query string) error {
+	opts, err := common.LoadOptions(configFile)
+	if err != nil {
+		return err
+	}
+	if nextTag != "" {
+		opts.NextTag = nextTag
+	}
+	if headerPattern != "" {
+		opts.HeaderPattern = headerPattern
+	}
+	if len(headerPatternMaps) > 0 {
+		opts.HeaderPatternMaps = headerPatternMaps
+	}
+	if noSemVer {
+		opts.UseSemVer = false
+		opts.TagFilterPattern = tagFilterPattern
+	}
+
+	scope := &common.RepoScope{
+		Paths: paths,
+	}
+	for _, spec := range components {
+		component, err := common.ParseComponent(spec)
+		if err != nil {
+			return err
+		}
+		scope.Components = append(scope.Components, component)
+	}
+	if len(includes) > 0 || len(excludes) > 0 {
+		filter := &types.CommitFilterConfig{
+			Include: includes,
+			Exclude: excludes,
+		}
+		if _, err := gitlib.NewCommitExprFilter(filter); err != nil {
+			return errors.Wrap(err, "filter of flags")
+		}
+		scope.Filters = append(scope.Filters, filter)
+	}
+
+	repoObj, workingDir, err := common.OpenRepository(repo, cacheDir, opts.NotesRef, noFetch)
+	if err != nil {
+		return err
+	}
+
+	gen, err := common.NewRepoGenerator(repoObj, workingDir, repoURL, templateFile, opts, scope)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrapf(err, "create output file %q", outputFile)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return gen.Generate(w, query)
+}
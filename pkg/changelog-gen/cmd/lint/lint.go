@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/log"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Check the config and commit overrides",
+		Long: `Check the config and commit overrides
+
+The changelog of each release is generated, then the commit overrides
+not matched by any commit in the configured ranges and the malformed
+git notes of commits are reported as warnings`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lint(configFile)
+		},
+	}
+)
+
+var (
+	configFile string
+	noFetch    bool
+	strict     bool
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file (required)")
+	Cmd.MarkFlagRequired("config")
+	Cmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch each repository")
+	Cmd.Flags().BoolVar(&strict, "strict", false, "Exit with non-zero code if any warning")
+}
+
+func lint(configFile string) error {
+	config, err := common.LoadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	if err := common.InitLocalRepos(config, noFetch); err != nil {
+		return errors.Wrap(err, "init local repository")
+	}
+
+	config.NoteErrors = new(types.CommitNoteErrors)
+	if _, err := changelog.NewGlobalGenerator(config).GetResults(); err != nil {
+		return errors.Wrap(err, "generate results")
+	}
+
+	noteErrs := config.NoteErrors.Errors()
+	for _, msg := range noteErrs {
+		log.Warningf("malformed note of %s", msg)
+	}
+
+	unused := make([]*types.CommitOverride, 0)
+	if config.Overrides != nil {
+		unused = config.Overrides.Unused()
+	}
+	for _, o := range unused {
+		log.Warningf("override of %s does not match any commit", o)
+	}
+
+	if strict && len(noteErrs) > 0 {
+		return errors.Errorf("%d notes are malformed", len(noteErrs))
+	}
+	if strict && len(unused) > 0 {
+		return errors.Errorf("%d overrides do not match any commit", len(unused))
+	}
+
+	return nil
+}
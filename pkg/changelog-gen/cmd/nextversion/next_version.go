@@ -0,0 +1,85 @@
+package nextversion
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "next-version",
+		Short: "Propose the next version of each release branch by unreleased commits",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nextVersion(configFile)
+		},
+	}
+)
+
+var (
+	configFile      string
+	noFetch         bool
+	outputFormat    string
+	allowBranchBump bool
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file (required)")
+	Cmd.MarkFlagRequired("config")
+	Cmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch each repository")
+	Cmd.Flags().StringVarP(&outputFormat, "output-format", "o", "text", "Output format, choices(`text|json`)")
+	Cmd.Flags().BoolVar(&allowBranchBump, "allow-branch-bump", false, "Allow minor and major bump leaving the release branch version")
+}
+
+func nextVersion(configFile string) error {
+	config, err := common.LoadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	if err := common.InitLocalRepos(config, noFetch); err != nil {
+		return errors.Wrap(err, "init local repository")
+	}
+
+	gen := changelog.NewGlobalGenerator(config)
+	result, err := gen.GetNextVersions(!allowBranchBump)
+	if err != nil {
+		return errors.Wrap(err, "get next versions")
+	}
+
+	return printNextVersions(os.Stdout, result, outputFormat)
+}
+
+func printNextVersions(w io.Writer, result *types.GlobalNextVersionResult, outputFormat string) error {
+	switch outputFormat {
+	case "json":
+		fmt.Fprintln(w, jsonutils.Marshal(result).PrettyString())
+		return nil
+	case "text":
+		for _, rls := range result.Releases {
+			if rls.Next == "" {
+				fmt.Fprintf(w, "%s (no unreleased commits)\n", rls.Branch)
+			} else {
+				fmt.Fprintf(w, "%s %s\n", rls.Branch, rls.Next)
+			}
+			for _, repo := range rls.Repos {
+				if repo.Next == "" {
+					fmt.Fprintf(w, "  %s: %s (no unreleased commits)\n", repo.Repo, repo.Current)
+					continue
+				}
+				fmt.Fprintf(w, "  %s: %s -> %s (%s, %d commits, %d breaking changes)\n", repo.Repo, repo.Current, repo.Next, repo.Bump, repo.Commits, repo.BreakingChanges)
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("Not support output format: %q", outputFormat)
+}
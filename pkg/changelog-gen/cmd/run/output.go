@@ -9,6 +9,7 @@ import (
 	"strings"
 	"text/template"
 
+	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"io/fs"
@@ -28,6 +29,12 @@ func handleOutput(data *types.GlobalRenderData, templateFile string, config *typ
 }
 
 func handleReleaseOutput(data *types.ReleaseRenderData, templateFile string, config *types.GlobalChangelogOutConfig) error {
+	if len(data.Versions) == 0 {
+		// all repos of the release failed with --keep-going
+		log.Warningf("release %q has no versions, skip output", data.Branch)
+		return nil
+	}
+
 	dir := strings.Replace(data.Branch, "/", "-", -1)
 	dir = strings.ReplaceAll(dir, ".", "_")
 	outDir := path.Join(config.Dir, dir)
@@ -67,7 +74,7 @@ import IndexDocCardList from '@site/src/components/IndexDocCardList';
 
 <IndexDocCardList />`
 
-	recentVersion := data.Versions[0]
+	recentVersion := recentReleasedVersion(data)
 	recentTag := recentVersion.Repos[0]
 	tagName := recentTag.Tag.Name
 	// date := recentTag.Tag.Date.Format("2006-01-02 15:04:05")
@@ -92,7 +99,7 @@ description: >
 weight: -%d
 ---`
 
-	recentVersion := data.Versions[0]
+	recentVersion := recentReleasedVersion(data)
 	recentTag := recentVersion.Repos[0]
 	tagName := recentTag.Tag.Name
 	// date := recentTag.Tag.Date.Format("2006-01-02 15:04:05")
@@ -107,6 +114,16 @@ weight: -%d
 	return nil
 }
 
+// recentReleasedVersion returns the newest version which is not draft
+func recentReleasedVersion(data *types.ReleaseRenderData) *types.GlobalVersionRenderData {
+	for _, version := range data.Versions {
+		if !version.Draft {
+			return version
+		}
+	}
+	return data.Versions[0]
+}
+
 func handleVersion(version *types.GlobalVersionRenderData, templateFile string, outDir string, isForDocus bool) error {
 	if _, err := os.Stat(templateFile); err != nil {
 		return errors.Wrapf(err, "stat template file")
@@ -3,16 +3,16 @@ package run
 import (
 	"fmt"
 	"io/ioutil"
-	"path"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"yunion.io/x/jsonutils"
+	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"github.com/yunionio/git-tools/pkg/changelog"
-	"github.com/yunionio/git-tools/pkg/gitlib"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
@@ -27,9 +27,13 @@ var (
 )
 
 var (
-	configFile   string
-	noFetch      bool
-	outputFormat string
+	configFile    string
+	noFetch       bool
+	outputFormat  string
+	preview       bool
+	nextTags      []string
+	keepGoing     bool
+	failureReport string
 )
 
 func init() {
@@ -37,120 +41,128 @@ func init() {
 	Cmd.MarkFlagRequired("config")
 	Cmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch each repository")
 	Cmd.Flags().StringVarP(&outputFormat, "output-format", "o", "", "Output format for raw render data, choices(`json|yaml`)")
+	Cmd.Flags().BoolVar(&preview, "preview", false, "Render unreleased commits of each release branch as a draft version, the version is computed if not set by --next")
+	Cmd.Flags().StringSliceVar(&nextTags, "next", nil, "Draft version of the release branch with the same major.minor (e.g. `v3.6.5`), implies --preview")
+	Cmd.Flags().BoolVarP(&keepGoing, "keep-going", "k", false, "Skip the failed repos and releases, render the others and report the failures")
+	Cmd.Flags().StringVar(&failureReport, "failure-report", "", "Write failures of --keep-going to the json `file`")
 }
 
-func initLocalRepos(config *types.GlobalChangeLogConfig) error {
-	for _, rls := range config.Releases {
-		for _, repo := range rls.Repos {
-			// set repo default name
-			repo.URL = strings.TrimRight(repo.URL, "/")
-			urlSegs := strings.Split(repo.URL, "/")
-			if len(urlSegs) == 0 {
-				return errors.Errorf("Invalid repo url %q", repo.URL)
-			}
-			if repo.Name == "" {
-				repo.Name = urlSegs[len(urlSegs)-1]
-			}
-			if repo.WorkingDir == "" {
-				repo.WorkingDir = path.Join(config.CacheDir, repo.Name)
-			}
-
-			repoObj, err := gitlib.NewRepository(repo.WorkingDir, repo.URL)
+// setPreviewNextTags sets draft version of each release branch by `--next`, or computes it from unreleased commits,
+// the branches without unreleased commits have no draft version
+func setPreviewNextTags(config *types.GlobalChangeLogConfig, gen *changelog.GlobalGenerator) error {
+	for _, next := range nextTags {
+		matched := false
+		for _, rls := range config.Releases {
+			branchVer, err := changelog.GetSemverBranchVersion(rls.Branch, config.Options.ReleaseBranchPatterns...)
 			if err != nil {
-				return errors.Wrapf(err, "newRepository %q", repo.URL)
+				return err
 			}
-
-			if noFetch {
-				return nil
+			if strings.HasPrefix(strings.TrimPrefix(next, "v"), branchVer+".") {
+				rls.NextTag = next
+				matched = true
 			}
-			if err := repoObj.Fetch(); err != nil {
-				return errors.Wrapf(err, "fetch repo %s", repoObj.LogPrefix())
+		}
+		if !matched {
+			return errors.Errorf("no release branch matches next version %q", next)
+		}
+	}
+
+	for _, rls := range config.Releases {
+		if rls.NextTag != "" {
+			continue
+		}
+		ret, err := gen.GetReleaseNextVersion(rls, true)
+		if err != nil {
+			err = errors.Wrapf(err, "get next version of branch %q", rls.Branch)
+			if !config.KeepGoing {
+				return err
 			}
+			// the failure is reported by generating the release
+			log.Warningf("skip preview: %v", err)
+			continue
 		}
+		if ret.Next == "" {
+			log.Infof("branch %q has no unreleased commits, skip preview", rls.Branch)
+			continue
+		}
+		rls.NextTag = ret.Next
 	}
 
 	return nil
 }
 
-func normalizeConfig(config *types.GlobalChangeLogConfig) {
-	if config.Output == nil {
-		config.Output = &types.GlobalChangelogOutConfig{
-			Dir: "./_output/changelog",
-		}
+func run(configFile string) error {
+	config, err := common.LoadConfig(configFile)
+	if err != nil {
+		return err
 	}
 
-	if config.Options == nil {
-		config.Options = new(types.ChangelogConfigOptions)
+	if err := common.InitLocalRepos(config, noFetch); err != nil {
+		return errors.Wrap(err, "init local repository")
 	}
+	config.KeepGoing = keepGoing
 
-	opt := config.Options
-	opt.UseSemVer = true
-	opt.NoMerges = true
-	if opt.CommitGroupTitleMaps == nil {
-		opt.CommitGroupTitleMaps = make(map[string]string)
+	gen := changelog.NewGlobalGenerator(config)
+	if preview || len(nextTags) > 0 {
+		if err := setPreviewNextTags(config, gen); err != nil {
+			return errors.Wrap(err, "set preview next tags")
+		}
 	}
 
-	for key, title := range map[string]string{
-		"feat":     "Features",
-		"fix":      "Bug Fixes",
-		"perf":     "Performance Improvements",
-		"refactor": "Code Refactoring",
-	} {
-		opt.CommitGroupTitleMaps[key] = title
+	result, err := gen.GetRenderData()
+	if err != nil {
+		return errors.Wrap(err, "generate render data")
 	}
 
-	if len(opt.HeaderPatternMaps) == 0 {
-		opt.HeaderPatternMaps = []string{"Type", "Scope", "Subject"}
-	}
-	if len(opt.HeaderPattern) == 0 {
-		opt.HeaderPattern = "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$"
-	}
-	if opt.CommitGroupBy == "" {
-		opt.CommitGroupBy = "Type"
-	}
-	if opt.CommitGroupSortBy == "" {
-		opt.CommitGroupSortBy = "Title"
-	}
-	if opt.CommitSortBy == "" {
-		opt.CommitSortBy = "Scope"
+	if config.Output.NotesDir != "" {
+		if err := mergeReleaseNotes(result, config.Output.NotesDir); err != nil {
+			return errors.Wrap(err, "merge release notes")
+		}
 	}
-	if len(opt.NoteKeywords) == 0 {
-		opt.NoteKeywords = []string{"BREAKING CHANGE"}
+
+	if err := processData(result, outputFormat, config.Template, config.Output); err != nil {
+		return err
 	}
+
+	return reportFailures(result.Failures, failureReport)
 }
 
-func run(configFile string) error {
-	content, err := ioutil.ReadFile(configFile)
-	if err != nil {
-		return errors.Wrapf(err, "read config file %q", configFile)
+// reportFailures logs the failures and writes them to report file, error is returned if any failure exists
+func reportFailures(failures []*types.RepoFailure, reportFile string) error {
+	if reportFile != "" {
+		content := jsonutils.Marshal(failures).PrettyString()
+		if err := ioutil.WriteFile(reportFile, []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "write failure report %q", reportFile)
+		}
 	}
 
-	jObj, err := jsonutils.ParseYAML(string(content))
-	if err != nil {
-		return errors.Wrapf(err, "parse config %s yaml content", configFile)
+	if len(failures) == 0 {
+		return nil
 	}
 
-	configV1 := new(types.GlobalChangeLogConfigV1)
-	if err := jObj.Unmarshal(configV1); err != nil {
-		return errors.Wrap(err, "load config")
-	}
-	config, err := configV1.ToInternalConfig()
-	if err != nil {
-		return errors.Wrap(err, "config to internal config")
+	for _, failure := range failures {
+		if failure.Repo == "" {
+			log.Errorf("release %q failed: %s", failure.Branch, failure.Error)
+		} else {
+			log.Errorf("release %q repo %q failed: %s", failure.Branch, failure.Repo, failure.Error)
+		}
 	}
-	normalizeConfig(config)
 
-	if err := initLocalRepos(config); err != nil {
-		return errors.Wrap(err, "init local repository")
-	}
+	return errors.Errorf("%d failures of repos or releases", len(failures))
+}
 
-	gen := changelog.NewGlobalGenerator(config)
-	result, err := gen.GetRenderData()
-	if err != nil {
-		return errors.Wrap(err, "generate render data")
+// mergeReleaseNotes merges hand-written notes into versions, notes of unknown versions are reported
+func mergeReleaseNotes(data *types.GlobalRenderData, notesDir string) error {
+	for _, rls := range data.Releases {
+		notes, err := changelog.LoadReleaseNotes(notesDir, rls.Branch)
+		if err != nil {
+			return errors.Wrapf(err, "load release notes of %q", rls.Branch)
+		}
+		for _, verStr := range changelog.MergeReleaseNotes(rls, notes) {
+			log.Warningf("release note of %q version %s is not merged, the version does not exist", rls.Branch, verStr)
+		}
 	}
-
-	return processData(result, outputFormat, config.Template, config.Output)
+	return nil
 }
 
 func processData(data *types.GlobalRenderData, outputFormat string, templateFile string, config *types.GlobalChangelogOutConfig) error {
@@ -0,0 +1,146 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/changelog"
+	"github.com/yunionio/git-tools/pkg/changelog-gen/cmd/common"
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show release statistics of each branch, version and repository",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stats(configFile)
+		},
+	}
+)
+
+var (
+	configFile   string
+	noFetch      bool
+	outputFormat string
+)
+
+const (
+	// allPlaceholder is used in branch and version summary rows
+	allPlaceholder = "*"
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file (required)")
+	Cmd.MarkFlagRequired("config")
+	Cmd.Flags().BoolVarP(&noFetch, "no-fetch", "n", false, "Not fetch each repository")
+	Cmd.Flags().StringVarP(&outputFormat, "output-format", "o", "table", "Output format, choices(`table|csv|json`)")
+}
+
+func stats(configFile string) error {
+	config, err := common.LoadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	if err := common.InitLocalRepos(config, noFetch); err != nil {
+		return errors.Wrap(err, "init local repository")
+	}
+
+	gen := changelog.NewGlobalGenerator(config)
+	results, err := gen.GetResults()
+	if err != nil {
+		return errors.Wrap(err, "get results")
+	}
+
+	data, err := changelog.NewGlobalStats(results)
+	if err != nil {
+		return errors.Wrap(err, "compute stats")
+	}
+
+	return printStats(os.Stdout, data, outputFormat)
+}
+
+func printStats(w io.Writer, data *types.GlobalStats, outputFormat string) error {
+	switch outputFormat {
+	case "json":
+		fmt.Fprintln(w, jsonutils.Marshal(data).PrettyString())
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(statsRows(data)); err != nil {
+			return errors.Wrap(err, "write csv")
+		}
+		return nil
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, row := range statsRows(data) {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return errors.Errorf("Not support output format: %q", outputFormat)
+}
+
+// statsRows flattens stats to rows, branch and version summaries use `*` as version and repo
+func statsRows(data *types.GlobalStats) [][]string {
+	rows := [][]string{
+		{"BRANCH", "VERSION", "REPO", "DATE", "PREVIOUS", "DAYS", "COMMITS", "BREAKING", "REVERTS", "CONTRIBUTORS", "FIRST_TIME", "TYPES", "SCOPES"},
+	}
+
+	newRow := func(branch, version, repo, date, previous string, days int, stats *types.Stats) []string {
+		return []string{
+			branch, version, repo, date, previous, strconv.Itoa(days),
+			strconv.Itoa(stats.Commits),
+			strconv.Itoa(stats.BreakingChanges),
+			strconv.Itoa(stats.Reverts),
+			strconv.Itoa(stats.Contributors),
+			strconv.Itoa(stats.FirstTimeContributors),
+			formatCounts(stats.Types),
+			formatCounts(stats.Scopes),
+		}
+	}
+
+	for _, rls := range data.Releases {
+		rows = append(rows, newRow(rls.Branch, allPlaceholder, allPlaceholder, "", "", 0, rls.Stats))
+		for _, ver := range rls.Versions {
+			rows = append(rows, newRow(rls.Branch, ver.TagName, allPlaceholder, ver.Date.Format("2006-01-02"), ver.PreviousTagName, ver.DaysSincePrevious, ver.Stats))
+			for _, repo := range ver.Repos {
+				rows = append(rows, newRow(rls.Branch, ver.TagName, repo.Repo, repo.Date.Format("2006-01-02"), repo.PreviousTagName, repo.DaysSincePrevious, repo.Stats))
+			}
+		}
+	}
+
+	return rows
+}
+
+// formatCounts formats counts as `key=count` ordered by count descending
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = fmt.Sprintf("%s=%d", k, counts[k])
+	}
+	return strings.Join(items, ";")
+}
@@ -0,0 +1,180 @@
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+// AuditTags checks whether the tags of repos are aligned on each release branch
+func (gen *GlobalGenerator) AuditTags() (*types.GlobalTagAudit, error) {
+	ret := &types.GlobalTagAudit{
+		Releases: make([]*types.ReleaseTagAudit, len(gen.config.Releases)),
+	}
+
+	for idx, rls := range gen.config.Releases {
+		repos := make([]*types.RepoTagAudit, len(rls.Repos))
+		for repoIdx, repo := range rls.Repos {
+			conf := gen.config.ToChangelogConfig(*rls, repoIdx)
+			tags, err := NewGenerator(conf, nil).GetSemverBranchTagAudits(conf.Branch)
+			if err != nil {
+				return nil, errors.Wrapf(err, "audit tags of repo %q on branch %q", repo.Name, rls.Branch)
+			}
+			repos[repoIdx] = &types.RepoTagAudit{
+				Repo: repo.Name,
+				Tags: tags,
+			}
+		}
+
+		rRet, err := AuditReleaseTags(rls.Branch, repos)
+		if err != nil {
+			return nil, errors.Wrapf(err, "audit tags on branch %q", rls.Branch)
+		}
+		ret.Releases[idx] = rRet
+	}
+
+	return ret, nil
+}
+
+// GetSemverBranchTagAudits reads tags of release branch and the branches containing them
+func (gen *Generator) GetSemverBranchTagAudits(branch string) ([]*types.TagAudit, error) {
+	back, err := gen.workdir()
+	if err != nil {
+		return nil, err
+	}
+	defer back()
+
+	tags, err := gen.GetSemverBranchTags(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*types.TagAudit, len(tags))
+	for idx, tag := range tags {
+		out, err := gen.client.Exec("branch", "--all", "--contains", tag.Name, "--format=%(refname:short)")
+		if err != nil {
+			return nil, errors.Wrapf(err, "list branches containing %q", tag.Name)
+		}
+
+		branches := make([]string, 0)
+		for _, line := range strings.Split(out, "\n") {
+			line = strings.TrimSpace(line)
+			// skip detached HEAD and symbolic refs like `origin/HEAD`
+			if line == "" || strings.HasPrefix(line, "(") || strings.HasSuffix(line, "HEAD") {
+				continue
+			}
+			branches = append(branches, line)
+		}
+
+		version := strings.TrimPrefix(tag.Name, "v")
+		if tag.Version != nil {
+			version = tag.Version.String()
+		}
+		ret[idx] = &types.TagAudit{
+			Name:     tag.Name,
+			Version:  version,
+			Branches: branches,
+		}
+	}
+
+	return ret, nil
+}
+
+// AuditReleaseTags builds the matrix of versions against repos and finds the issues:
+//   - missing: version between the oldest and latest tags of repo is not tagged
+//   - skew: latest version of repo is behind the release
+//   - wrong-branch: tag is not reachable from the release branch
+//   - unreachable: tag is not reachable from any branch
+func AuditReleaseTags(branch string, repos []*types.RepoTagAudit) (*types.ReleaseTagAudit, error) {
+	ret := &types.ReleaseTagAudit{
+		Branch: branch,
+		Repos:  repos,
+		Issues: make([]*types.TagIssue, 0),
+	}
+
+	parsed := make(map[string]semver.Version)
+	for _, repo := range repos {
+		for _, tag := range repo.Tags {
+			if _, ok := parsed[tag.Version]; ok {
+				continue
+			}
+			ver, err := semver.Parse(tag.Version)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse version of tag %q, repo %q", tag.Name, repo.Repo)
+			}
+			parsed[tag.Version] = ver
+			ret.Versions = append(ret.Versions, tag.Version)
+		}
+	}
+	sort.Slice(ret.Versions, func(i, j int) bool {
+		return parsed[ret.Versions[i]].GT(parsed[ret.Versions[j]])
+	})
+
+	addIssue := func(repo string, version string, kind string, format string, args ...interface{}) {
+		ret.Issues = append(ret.Issues, &types.TagIssue{
+			Repo:    repo,
+			Version: version,
+			Kind:    kind,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, repo := range repos {
+		if len(repo.Tags) == 0 {
+			if len(ret.Versions) > 0 {
+				addIssue(repo.Repo, ret.Versions[0], types.TagIssueSkew, "no tags, release latest is %s", ret.Versions[0])
+			}
+			continue
+		}
+
+		oldest, latest := repo.Tags[0].Version, repo.Tags[0].Version
+		for _, tag := range repo.Tags {
+			if parsed[tag.Version].LT(parsed[oldest]) {
+				oldest = tag.Version
+			}
+			if parsed[tag.Version].GT(parsed[latest]) {
+				latest = tag.Version
+			}
+		}
+
+		if latest != ret.Versions[0] {
+			addIssue(repo.Repo, ret.Versions[0], types.TagIssueSkew, "latest is %s, release latest is %s", latest, ret.Versions[0])
+		}
+
+		for _, version := range ret.Versions {
+			ver := parsed[version]
+			if ver.GT(parsed[latest]) || ver.LT(parsed[oldest]) {
+				continue
+			}
+			if repo.Tag(version) == nil {
+				addIssue(repo.Repo, version, types.TagIssueMissing, "version %s is not tagged", version)
+			}
+		}
+
+		for _, tag := range repo.Tags {
+			if len(tag.Branches) == 0 {
+				addIssue(repo.Repo, tag.Version, types.TagIssueUnreachable, "tag %s is not reachable from any branch", tag.Name)
+			} else if !containsBranch(tag.Branches, branch) {
+				addIssue(repo.Repo, tag.Version, types.TagIssueWrongBranch, "tag %s is on %s", tag.Name, strings.Join(tag.Branches, ", "))
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// containsBranch checks the local and 'origin' remote branch
+func containsBranch(branches []string, branch string) bool {
+	for _, b := range branches {
+		if b == branch || b == "origin/"+branch {
+			return true
+		}
+	}
+	return false
+}
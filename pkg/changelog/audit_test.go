@@ -0,0 +1,56 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestAuditReleaseTags(t *testing.T) {
+	assert := assert.New(t)
+
+	onBranch := []string{"origin/release/3.6"}
+	repos := []*types.RepoTagAudit{
+		{
+			Repo: "cloudpods",
+			Tags: []*types.TagAudit{
+				{Name: "v3.6.10", Version: "3.6.10", Branches: onBranch},
+				{Name: "v3.6.9", Version: "3.6.9", Branches: onBranch},
+				{Name: "v3.6.8", Version: "3.6.8", Branches: onBranch},
+			},
+		},
+		{
+			Repo: "dashboard",
+			Tags: []*types.TagAudit{
+				{Name: "v3.6.10", Version: "3.6.10", Branches: []string{"origin/master"}},
+				{Name: "v3.6.8", Version: "3.6.8", Branches: []string{"release/3.6"}},
+			},
+		},
+		{
+			Repo: "ocadm",
+			Tags: []*types.TagAudit{
+				{Name: "v3.6.9", Version: "3.6.9", Branches: nil},
+			},
+		},
+		{
+			Repo: "sdnagent",
+		},
+	}
+
+	ret, err := AuditReleaseTags("release/3.6", repos)
+	assert.Nil(err)
+	assert.Equal([]string{"3.6.10", "3.6.9", "3.6.8"}, ret.Versions)
+	assert.Equal([]*types.TagIssue{
+		{Repo: "dashboard", Version: "3.6.9", Kind: types.TagIssueMissing, Message: "version 3.6.9 is not tagged"},
+		{Repo: "dashboard", Version: "3.6.10", Kind: types.TagIssueWrongBranch, Message: "tag v3.6.10 is on origin/master"},
+		{Repo: "ocadm", Version: "3.6.10", Kind: types.TagIssueSkew, Message: "latest is 3.6.9, release latest is 3.6.10"},
+		{Repo: "ocadm", Version: "3.6.9", Kind: types.TagIssueUnreachable, Message: "tag v3.6.9 is not reachable from any branch"},
+		{Repo: "sdnagent", Version: "3.6.10", Kind: types.TagIssueSkew, Message: "no tags, release latest is 3.6.10"},
+	}, ret.Issues)
+
+	ret, err = AuditReleaseTags("release/3.6", repos[:1])
+	assert.Nil(err)
+	assert.Len(ret.Issues, 0)
+}
@@ -0,0 +1,138 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// UnreleasedSectionName is the anchor name of unreleased section
+	UnreleasedSectionName = "unreleased"
+)
+
+var (
+	// section starts with anchor line, e.g. `<a name="v1.0.0"></a>`
+	reSectionAnchor = regexp.MustCompile(`^<a name="([^"]+)"></a>\s*$`)
+)
+
+// ChangelogFile is a CHANGELOG markdown split by version anchors
+type ChangelogFile struct {
+	// Preamble is the content before the first section
+	Preamble string
+	Sections []*ChangelogSection
+}
+
+// ChangelogSection is the content from a version anchor to the next one
+type ChangelogSection struct {
+	// Name of anchor, tag name or `unreleased`
+	Name    string
+	Content string
+}
+
+// ParseChangelogFile splits content into sections, `String()` of the result is the same as content
+func ParseChangelogFile(content string) *ChangelogFile {
+	file := &ChangelogFile{}
+
+	var cur *ChangelogSection
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if res := reSectionAnchor.FindStringSubmatch(strings.TrimRight(line, "\r\n")); len(res) > 0 {
+			cur = &ChangelogSection{Name: res[1]}
+			file.Sections = append(file.Sections, cur)
+		}
+		if cur == nil {
+			file.Preamble += line
+		} else {
+			cur.Content += line
+		}
+	}
+
+	return file
+}
+
+func (f *ChangelogFile) String() string {
+	var sb strings.Builder
+	sb.WriteString(f.Preamble)
+	for _, s := range f.Sections {
+		sb.WriteString(s.Content)
+	}
+	return sb.String()
+}
+
+// Section returns the section by name
+func (f *ChangelogFile) Section(name string) *ChangelogSection {
+	for _, s := range f.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// LatestVersion returns the name of the first version section
+func (f *ChangelogFile) LatestVersion() string {
+	for _, s := range f.Sections {
+		if s.Name != UnreleasedSectionName {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// Merge inserts the version sections of generated not in f and replaces the unreleased section,
+// the existing version sections and preamble are kept as they are
+func (f *ChangelogFile) Merge(generated *ChangelogFile) {
+	if len(f.Sections) == 0 && strings.TrimSpace(f.Preamble) == "" {
+		f.Preamble = generated.Preamble
+	}
+
+	sections := make([]*ChangelogSection, 0, len(f.Sections))
+	for _, s := range f.Sections {
+		if s.Name != UnreleasedSectionName {
+			sections = append(sections, s)
+		}
+	}
+
+	existIndex := func(name string) int {
+		for i, s := range sections {
+			if s.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// generated sections are ordered from newest to oldest, insert the new one
+	// before the next generated section which already exists
+	for i := len(generated.Sections) - 1; i >= 0; i-- {
+		s := generated.Sections[i]
+		if s.Name == UnreleasedSectionName || existIndex(s.Name) >= 0 {
+			continue
+		}
+
+		pos := len(sections)
+		for _, older := range generated.Sections[i+1:] {
+			if idx := existIndex(older.Name); idx >= 0 {
+				pos = idx
+				break
+			}
+		}
+		sections = append(sections[:pos], append([]*ChangelogSection{separated(s)}, sections[pos:]...)...)
+	}
+
+	if unreleased := generated.Section(UnreleasedSectionName); unreleased != nil {
+		sections = append([]*ChangelogSection{separated(unreleased)}, sections...)
+	}
+
+	f.Sections = sections
+}
+
+// separated makes sure the section ends with a blank line
+func separated(s *ChangelogSection) *ChangelogSection {
+	if strings.HasSuffix(s.Content, "\n\n") {
+		return s
+	}
+	return &ChangelogSection{
+		Name:    s.Name,
+		Content: strings.TrimRight(s.Content, "\n") + "\n\n",
+	}
+}
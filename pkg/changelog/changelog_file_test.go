@@ -0,0 +1,90 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelogFileMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	content := `# CHANGELOG
+
+Hand written preamble.
+
+<a name="unreleased"></a>
+## [Unreleased]
+- old unreleased
+
+<a name="v1.1.0"></a>
+## v1.1.0
+- edited by hand   
+
+<a name="v1.0.0"></a>
+## v1.0.0
+- init
+
+[v1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`
+
+	file := ParseChangelogFile(content)
+	assert.Equal(content, file.String())
+	assert.Equal("v1.1.0", file.LatestVersion())
+	assert.Len(file.Sections, 3)
+
+	generated := ParseChangelogFile(`# Generated
+
+<a name="unreleased"></a>
+## [Unreleased]
+- new unreleased
+
+<a name="v1.2.1"></a>
+## v1.2.1
+- generated
+
+<a name="v1.2.0"></a>
+## v1.2.0
+- generated
+
+<a name="v1.1.0"></a>
+## v1.1.0
+- generated`)
+
+	file.Merge(generated)
+	assert.Equal(`# CHANGELOG
+
+Hand written preamble.
+
+<a name="unreleased"></a>
+## [Unreleased]
+- new unreleased
+
+<a name="v1.2.1"></a>
+## v1.2.1
+- generated
+
+<a name="v1.2.0"></a>
+## v1.2.0
+- generated
+
+<a name="v1.1.0"></a>
+## v1.1.0
+- edited by hand   
+
+<a name="v1.0.0"></a>
+## v1.0.0
+- init
+
+[v1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`, file.String())
+
+	// unreleased section is removed once released
+	file.Merge(ParseChangelogFile(""))
+	assert.Nil(file.Section(UnreleasedSectionName))
+
+	// empty file takes generated preamble
+	empty := ParseChangelogFile("")
+	empty.Merge(generated)
+	assert.Equal(generated.String()+"\n\n", empty.String())
+}
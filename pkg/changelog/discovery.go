@@ -0,0 +1,98 @@
+package changelog
+
+import (
+	"sort"
+
+	"github.com/blang/semver/v4"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	// DefaultReleaseBranchPatterns matches `release/major.minor` and `release-major.minor`
+	DefaultReleaseBranchPatterns = []string{
+		`release[\/-]([\d]+\.[\d]+$)`,
+	}
+)
+
+// DiscoverReleases groups release branches of repos by `major.minor` version,
+// `repoBranches` are the branch names of each repo in `conf.Repos`, the branch of repo named differently
+// from the release (e.g. `release-3.10` of `release/3.10`) is recorded in `RepoBranches`.
+// Releases are sorted from the newest, and releases in `exclude` versions are skipped
+func DiscoverReleases(
+	conf *types.ReleaseDiscoveryConfig,
+	patterns []string,
+	repoBranches [][]string,
+	exclude map[string]bool,
+) ([]*types.ReleaseChangeLogConfig, error) {
+	var minVer *semver.Version
+	if conf.MinVersion != "" {
+		ver, err := semver.ParseTolerant(conf.MinVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse min version %q", conf.MinVersion)
+		}
+		minVer = &ver
+	}
+
+	releases := make(map[string]*types.ReleaseChangeLogConfig)
+	versions := make(map[string]semver.Version)
+	for idx, branches := range repoBranches {
+		repo := conf.Repos[idx]
+		for _, branch := range branches {
+			verStr, err := GetSemverBranchVersion(branch, patterns...)
+			if err != nil {
+				continue
+			}
+			if exclude[verStr] {
+				continue
+			}
+			ver, err := semver.ParseTolerant(verStr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse version of branch %q", branch)
+			}
+			if minVer != nil && ver.LT(*minVer) {
+				continue
+			}
+
+			rls, ok := releases[verStr]
+			if !ok {
+				rls = &types.ReleaseChangeLogConfig{
+					Branch: branch,
+				}
+				releases[verStr] = rls
+				versions[verStr] = ver
+			}
+			if len(rls.Repos) > 0 && rls.Repos[len(rls.Repos)-1] == repo {
+				// the repo has several branches of the same version
+				continue
+			}
+			rls.Repos = append(rls.Repos, repo)
+			if branch != rls.Branch {
+				if rls.RepoBranches == nil {
+					rls.RepoBranches = make(map[string]string)
+				}
+				rls.RepoBranches[repo.Name] = branch
+			}
+		}
+	}
+
+	verStrs := make([]string, 0, len(releases))
+	for verStr := range releases {
+		verStrs = append(verStrs, verStr)
+	}
+	sort.Slice(verStrs, func(i, j int) bool {
+		return versions[verStrs[i]].GT(versions[verStrs[j]])
+	})
+	if conf.Latest > 0 && len(verStrs) > conf.Latest {
+		verStrs = verStrs[:conf.Latest]
+	}
+
+	ret := make([]*types.ReleaseChangeLogConfig, len(verStrs))
+	for i, verStr := range verStrs {
+		ret[i] = releases[verStr]
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,80 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestGetSemverBranchVersionPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := GetSemverBranchVersion("v3.4-stable")
+	assert.NotNil(err)
+
+	patterns := []string{`^v(?P<version>\d+\.\d+)-stable$`, `^(?P<prefix>rel)-(?P<version>\d+\.\d+)$`}
+	ver, err := GetSemverBranchVersion("v3.4-stable", patterns...)
+	assert.Nil(err)
+	assert.Equal("3.4", ver)
+	ver, err = GetSemverBranchVersion("rel-3.10", patterns...)
+	assert.Nil(err)
+	assert.Equal("3.10", ver)
+	_, err = GetSemverBranchVersion("release/3.4", patterns...)
+	assert.NotNil(err)
+
+	_, err = GetSemverBranchVersion("release/3.4", "release/(")
+	assert.NotNil(err)
+}
+
+func TestDiscoverReleases(t *testing.T) {
+	assert := assert.New(t)
+
+	repoA := &types.Repository{Name: "a"}
+	repoB := &types.Repository{Name: "b"}
+	conf := &types.ReleaseDiscoveryConfig{
+		Repos: []*types.Repository{repoA, repoB},
+	}
+	repoBranches := [][]string{
+		{"master", "release/3.2", "release/3.9", "release/3.10", "release-3.10", "release/3.11"},
+		{"master", "release/3.9", "release/3.10", "feature/foo"},
+	}
+
+	releases, err := DiscoverReleases(conf, nil, repoBranches, nil)
+	assert.Nil(err)
+	branches := func(rlss []*types.ReleaseChangeLogConfig) []string {
+		ret := make([]string, len(rlss))
+		for i, rls := range rlss {
+			ret[i] = rls.Branch
+		}
+		return ret
+	}
+	assert.Equal([]string{"release/3.11", "release/3.10", "release/3.9", "release/3.2"}, branches(releases))
+	assert.Equal([]*types.Repository{repoA}, releases[0].Repos)
+	assert.Equal([]*types.Repository{repoA, repoB}, releases[1].Repos)
+	assert.Nil(releases[1].RepoBranches)
+	assert.Equal("release/3.10", releases[1].RepoBranch(1))
+
+	conf.Latest = 2
+	conf.MinVersion = "3.10"
+	releases, err = DiscoverReleases(conf, nil, repoBranches, map[string]bool{"3.11": true})
+	assert.Nil(err)
+	assert.Equal([]string{"release/3.10"}, branches(releases))
+
+	// repo b names the branch differently
+	repoBranches[1] = []string{"release-3.10"}
+	conf.Latest = 0
+	conf.MinVersion = ""
+	releases, err = DiscoverReleases(conf, nil, repoBranches, nil)
+	assert.Nil(err)
+	assert.Equal("release/3.10", releases[1].Branch)
+	assert.Equal(map[string]string{"b": "release-3.10"}, releases[1].RepoBranches)
+	assert.Equal("release/3.10", releases[1].RepoBranch(0))
+	assert.Equal("release-3.10", releases[1].RepoBranch(1))
+	assert.Equal("release-3.10", releases[1].ToChangelogConfig("git", nil, 1).Branch)
+
+	conf.MinVersion = "3.x"
+	_, err = DiscoverReleases(conf, nil, repoBranches, nil)
+	assert.NotNil(err)
+}
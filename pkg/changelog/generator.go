@@ -1,16 +1,20 @@
 package changelog
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
 
+	"github.com/blang/semver/v4"
 	gitcmd "github.com/tsuyoshiwada/go-gitcmd"
 
+	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"github.com/yunionio/git-tools/pkg/gitlib"
@@ -26,6 +30,8 @@ type Generator struct {
 	commitParser    gitlib.CommitParser
 	commitExtractor gitlib.CommitExtractor
 	processor       gitlib.Processor
+	// authors are the identities reachable from previous tags, it's built once per repo
+	authors *authorIndex
 }
 
 // NewGenerator receives `Config` and create an new `Generator`
@@ -51,9 +57,9 @@ func NewGenerator(config *types.ChangelogConfig, processor gitlib.Processor) *Ge
 		client:          cli,
 		config:          config,
 		tagReader:       tagReader,
-		tagSelector:     gitlib.NewTagSelector(),
+		tagSelector:     gitlib.NewGitTagSelector(cli),
 		commitParser:    gitlib.NewCommitParser(cli, config),
-		commitExtractor: gitlib.NewCommitExtractor(config.Options),
+		commitExtractor: gitlib.NewCommitExtractor(config.Options, config.Filters...),
 		processor:       processor,
 	}
 }
@@ -110,6 +116,8 @@ func (gen *Generator) workdir() (func() error, error) {
 //  <tagname>..  - Commit from the `<tagname>` to the latest tag (e.g. `1.0.0..`)
 //  ..<tagname>  - Commit from the oldest tag to `<tagname>` (e.g. `..1.0.0`)
 //  <tagname>    - Commit contained in `<tagname>` (e.g. `1.0.0`)
+//
+// endpoints can also be dates, commits or branches (e.g. `since:2024-01-01..until:2024-06-30`, `v1.0.0..release/1.2`)
 func (gen *Generator) Generate(w io.Writer, query string) error {
 	unreleased, versions, err := gen.GetResults(query)
 	if err != nil {
@@ -119,6 +127,35 @@ func (gen *Generator) Generate(w io.Writer, query string) error {
 	return gen.render(w, unreleased, versions)
 }
 
+// UpdateFile inserts the sections of new tags and replaces the unreleased section of CHANGELOG file,
+// the other sections are kept as they are. The `query` is `<latest tag in file>..` if it's empty
+func (gen *Generator) UpdateFile(fileName string, query string) error {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "read %q", fileName)
+	}
+
+	file := ParseChangelogFile(string(content))
+	if query == "" {
+		if latest := file.LatestVersion(); latest != "" {
+			query = latest + ".."
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gen.Generate(buf, query); err != nil {
+		return errors.Wrapf(err, "generate by query %q", query)
+	}
+
+	file.Merge(ParseChangelogFile(buf.String()))
+
+	if err := ioutil.WriteFile(fileName, []byte(file.String()), 0644); err != nil {
+		return errors.Wrapf(err, "write %q", fileName)
+	}
+
+	return nil
+}
+
 func (gen *Generator) GeneratorBySemverBranch(w io.Writer, branch string) error {
 	unreleased, versions, err := gen.GetSemverBranchResults(branch)
 	if err != nil {
@@ -131,7 +168,7 @@ func (gen *Generator) GeneratorBySemverBranch(w io.Writer, branch string) error
 // GetSemverBranchTags read tags according by branch
 // branch format is `release/major.minor`
 func (gen *Generator) GetSemverBranchTags(branch string) ([]*types.Tag, error) {
-	branchVer, err := GetSemverBranchVersion(branch)
+	branchVer, err := GetSemverBranchVersion(branch, gen.config.Options.ReleaseBranchPatterns...)
 	if err != nil {
 		return nil, err
 	}
@@ -144,11 +181,33 @@ func (gen *Generator) GetSemverBranchTags(branch string) ([]*types.Tag, error) {
 	return filterTagsByPrefix(branchVer, tags), nil
 }
 
+// GetSemverBranchVersions returns the versions of tags on branch
+func (gen *Generator) GetSemverBranchVersions(branch string) ([]string, error) {
+	back, err := gen.workdir()
+	if err != nil {
+		return nil, err
+	}
+	defer back()
+
+	tags, err := gen.GetSemverBranchTags(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Version != nil {
+			versions = append(versions, tag.Version.String())
+		}
+	}
+	return versions, nil
+}
+
 func filterTagsByPrefix(branchVer string, tags []*types.Tag) []*types.Tag {
 	ret := make([]*types.Tag, 0)
 
 	for _, tag := range tags {
-		if strings.HasPrefix(tag.Name, "v"+branchVer) {
+		if strings.HasPrefix(tag.Name, "v"+branchVer+".") {
 			ret = append(ret, tag)
 		}
 	}
@@ -157,16 +216,31 @@ func filterTagsByPrefix(branchVer string, tags []*types.Tag) []*types.Tag {
 }
 
 // GetSemverBranchVersion read branch semantic version string
-// branch format is `release/major.minor`
-func GetSemverBranchVersion(branch string) (string, error) {
-	reRef := regexp.MustCompile(`release[\/-]([\d]+\.[\d]+$)`)
-	res := reRef.FindAllStringSubmatch(branch, -1)
-	if len(res) == 0 {
-		return "", errors.Errorf("branch %q is not release branch", branch)
+// branch format is `release/major.minor` if patterns are not specified,
+// otherwise the version is captured by the named group `version` or the first group of pattern
+func GetSemverBranchVersion(branch string, patterns ...string) (string, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultReleaseBranchPatterns
+	}
+
+	for _, pattern := range patterns {
+		reRef, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid release branch pattern %q", pattern)
+		}
+		res := reRef.FindStringSubmatch(branch)
+		if len(res) < 2 {
+			continue
+		}
+
+		idx := reRef.SubexpIndex("version")
+		if idx < 0 {
+			idx = 1
+		}
+		return res[idx], nil
 	}
 
-	branchVer := res[0][1]
-	return branchVer, nil
+	return "", errors.Errorf("branch %q is not release branch", branch)
 }
 
 func (gen *Generator) GetSemverBranchQuery(branch string) (string, error) {
@@ -199,9 +273,73 @@ func (gen *Generator) GetSemverBranchResults(branch string) (*types.Unreleased,
 		return nil, nil, err
 	}
 
+	if err := gen.setBranchHeadRef(branch); err != nil {
+		log.Warningf("%v, unreleased commits are read from HEAD", err)
+	}
+
 	return gen.GetResults(query)
 }
 
+// GetSemverBranchUnreleased returns the latest tag of branch and the commits after it,
+// the contributors of unreleased are not read
+func (gen *Generator) GetSemverBranchUnreleased(branch string) (*types.Tag, *types.Unreleased, error) {
+	if err := gen.setBranchHeadRef(branch); err != nil {
+		return nil, nil, err
+	}
+
+	back, err := gen.workdir()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer back()
+
+	tags, err := gen.GetSemverBranchTags(branch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tags) == 0 {
+		return nil, nil, errors.Errorf("branch %q not found tags", branch)
+	}
+
+	unreleased, err := gen.readUnreleased(tags[:1], gen.processor, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tags[0], unreleased, nil
+}
+
+// setBranchHeadRef uses the branch as `HeadRef` if it's not specified,
+// the remote tracking branch takes precedence over the local one
+func (gen *Generator) setBranchHeadRef(branch string) error {
+	if gen.config.HeadRef != "" {
+		return nil
+	}
+
+	back, err := gen.workdir()
+	if err != nil {
+		return err
+	}
+	defer back()
+
+	for _, ref := range []string{"refs/remotes/origin/" + branch, "refs/heads/" + branch} {
+		if _, err := gen.client.Exec("rev-parse", "--verify", "--quiet", ref); err == nil {
+			gen.config.HeadRef = ref
+			return nil
+		}
+	}
+
+	return errors.Errorf("branch %q not found", branch)
+}
+
+func (gen *Generator) headRef() string {
+	if gen.config.HeadRef != "" {
+		return gen.config.HeadRef
+	}
+	return "HEAD"
+}
+
 func (gen *Generator) GetResults(query string) (*types.Unreleased, []*types.Version, error) {
 	back, err := gen.workdir()
 	if err != nil {
@@ -214,7 +352,21 @@ func (gen *Generator) GetResults(query string) (*types.Unreleased, []*types.Vers
 		return nil, nil, err
 	}
 
-	unreleased, err := gen.readUnreleased(tags, gen.processor)
+	// previous tags of versions and unreleased from oldest to newest
+	revs := make([]string, 0, len(tags)+1)
+	if first != "" {
+		revs = append(revs, first)
+	}
+	for i := len(tags) - 1; i >= 0; i-- {
+		if tags[i].Name != gen.config.Options.NextTag {
+			revs = append(revs, tags[i].Name)
+		}
+	}
+	if err := gen.readAuthorIndex(revs); err != nil {
+		return nil, nil, errors.Wrap(err, "read authors")
+	}
+
+	unreleased, err := gen.readUnreleased(tags, gen.processor, true)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -237,35 +389,48 @@ func (gen *Generator) readVersions(tags []*types.Tag, first string, processor gi
 
 	for i, tag := range tags {
 		var (
-			isNext = next == tag.Name
-			rev    string
+			isNext   = next == tag.Name
+			rev      string
+			previous string
 		)
 
 		if isNext {
 			if tag.Previous != nil {
-				rev = tag.Previous.Name + "..HEAD"
+				previous = tag.Previous.Name
+				rev = tag.Previous.Name + ".." + gen.headRef()
 			} else {
-				rev = "HEAD"
+				rev = gen.headRef()
 			}
 		} else {
 			if i+1 < len(tags) {
-				rev = tags[i+1].Name + ".." + tag.Name
+				previous = tags[i+1].Name
 			} else {
-				if first != "" {
-					rev = first + ".." + tag.Name
-				} else {
-					rev = tag.Name
-				}
+				previous = first
+			}
+			base, err := gen.versionBase(tag, previous)
+			if err != nil {
+				return nil, errors.Wrapf(err, "get base of %q", tag.Name)
+			}
+			if base != "" {
+				rev = base + ".." + tag.Name
+			} else {
+				rev = tag.Name
 			}
 		}
 
-		commits, err := gen.commitParser.Parse(rev, processor)
+		commits, credits, err := gen.readCommits(rev, processor)
 		if err != nil {
 			return nil, err
 		}
 
 		commitGroups, mergeCommits, revertCommits, noteGroups := gen.commitExtractor.Extract(commits)
 
+		contributors, err := gen.readContributors(credits, previous)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read contributors of %q", tag.Name)
+		}
+		processContributors(contributors, processor)
+
 		versions = append(versions, &types.Version{
 			Tag:           tag,
 			CommitGroups:  commitGroups,
@@ -273,6 +438,9 @@ func (gen *Generator) readVersions(tags []*types.Tag, first string, processor gi
 			MergeCommits:  mergeCommits,
 			RevertCommits: revertCommits,
 			NoteGroups:    noteGroups,
+			Contributors:  contributors,
+			Components:    gen.extractComponents(commits),
+			DiffStat:      gen.diffStat(commits),
 		})
 
 		// Instead of `getTags()`, assign the date to the tag
@@ -284,35 +452,406 @@ func (gen *Generator) readVersions(tags []*types.Tag, first string, processor gi
 	return versions, nil
 }
 
-func (gen *Generator) readUnreleased(tags []*types.Tag, processor gitlib.Processor) (*types.Unreleased, error) {
+// versionBase returns the start of commits of tag. The first version of a release line starts from
+// `BaseRef` if configured, otherwise from the fork point with the previous release line,
+// so that `x.y.0` contains only the commits new in the line
+func (gen *Generator) versionBase(tag *types.Tag, previous string) (string, error) {
+	if !isReleaseLineStart(tag, previous) {
+		return previous, nil
+	}
+
+	if gen.config.BaseRef != "" {
+		return gen.resolveRef(gen.config.BaseRef)
+	}
+
+	lineRef, err := gen.previousReleaseLine(tag)
+	if err != nil {
+		return "", errors.Wrap(err, "find previous release line")
+	}
+	if lineRef == "" {
+		lineRef = previous
+	}
+	if lineRef == "" {
+		return "", nil
+	}
+
+	out, err := gen.client.Exec("merge-base", lineRef, tag.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "merge-base of %q and %q", lineRef, tag.Name)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// previousReleaseLine returns the newest tag of the release lines older than tag,
+// or the newest older release branch if there is no such tag
+func (gen *Generator) previousReleaseLine(tag *types.Tag) (string, error) {
+	tags, err := gen.tagReader.ReadAll()
+	if err != nil {
+		return "", errors.Wrap(err, "read all tags")
+	}
+
+	var newest *types.Tag
+	for _, t := range tags {
+		if t.Version == nil || !isOlderReleaseLine(*t.Version, *tag.Version) {
+			continue
+		}
+		if newest == nil || t.Version.GT(*newest.Version) {
+			newest = t
+		}
+	}
+	if newest != nil {
+		return newest.Name, nil
+	}
+
+	out, err := gen.client.Exec("for-each-ref", "--format=%(refname)", "refs/remotes/origin/", "refs/heads/")
+	if err != nil {
+		return "", errors.Wrap(err, "list branches")
+	}
+
+	var (
+		branchRef string
+		branchVer *semver.Version
+	)
+	for _, ref := range strings.Split(out, "\n") {
+		ref = strings.TrimSpace(ref)
+		branch := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/remotes/origin/"), "refs/heads/")
+		verStr, err := GetSemverBranchVersion(branch, gen.config.Options.ReleaseBranchPatterns...)
+		if err != nil {
+			continue
+		}
+		ver, err := semver.ParseTolerant(verStr)
+		if err != nil || !isOlderReleaseLine(ver, *tag.Version) {
+			continue
+		}
+		if branchVer == nil || ver.GT(*branchVer) {
+			branchRef, branchVer = ref, &ver
+		}
+	}
+
+	return branchRef, nil
+}
+
+// isOlderReleaseLine reports whether the `major.minor` of ver is older than the one of line
+func isOlderReleaseLine(ver semver.Version, line semver.Version) bool {
+	if ver.Major != line.Major {
+		return ver.Major < line.Major
+	}
+	return ver.Minor < line.Minor
+}
+
+// isReleaseLineStart reports whether the tag is the first one of its `major.minor` line,
+// previous which is not a semantic version tag (e.g. commit) is treated as the same line
+func isReleaseLineStart(tag *types.Tag, previous string) bool {
+	if tag.Version == nil {
+		return false
+	}
+	if previous == "" {
+		return true
+	}
+
+	prevVer, err := semver.Parse(strings.TrimPrefix(previous, "v"))
+	if err != nil {
+		return false
+	}
+	return prevVer.Major != tag.Version.Major || prevVer.Minor != tag.Version.Minor
+}
+
+// resolveRef resolves the reference, the remote tracking branch of 'origin' is also tried
+func (gen *Generator) resolveRef(ref string) (string, error) {
+	for _, r := range []string{ref, "refs/remotes/origin/" + ref} {
+		if out, err := gen.client.Exec("rev-parse", "--verify", "--quiet", r+"^{commit}"); err == nil {
+			return strings.TrimSpace(out), nil
+		}
+	}
+	return "", errors.Errorf("reference %q not found", ref)
+}
+
+// readCommits parses commits of rev, drops the ones excluded by filters and runs processor on the others.
+// The credits are the identities of commits copied before processing,
+// so that the email policy of processor does not affect the first-time detection
+func (gen *Generator) readCommits(rev string, processor gitlib.Processor) ([]*types.Commit, []*commitCredits, error) {
+	commits, err := gen.commitParser.Parse(rev, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	commits = gen.commitExtractor.Filter(commits)
+
+	processed := make([]*types.Commit, 0, len(commits))
+	credits := make([]*commitCredits, 0, len(commits))
+	for _, commit := range commits {
+		credit := newCommitCredits(commit)
+		if processor != nil {
+			commit = processor.ProcessCommit(commit)
+			if commit == nil {
+				continue
+			}
+		}
+		processed = append(processed, commit)
+		credits = append(credits, credit)
+	}
+
+	return processed, credits, nil
+}
+
+// commitCredits are the authors, co-authors and reviewers of commit
+type commitCredits struct {
+	authors   []*types.CommitContributor
+	reviewers []*types.CommitContributor
+}
+
+func newCommitCredits(commit *types.Commit) *commitCredits {
+	copyAll := func(contributors []*types.CommitContributor) []*types.CommitContributor {
+		ret := make([]*types.CommitContributor, len(contributors))
+		for i, c := range contributors {
+			cc := *c
+			ret[i] = &cc
+		}
+		return ret
+	}
+
+	return &commitCredits{
+		authors:   copyAll(commit.Contributors()),
+		reviewers: copyAll(commit.Reviewers()),
+	}
+}
+
+// readContributors counts the authors, co-authors and reviewers of commits,
+// the contributor is first-time if no commits reachable from `previous` are authored by the same name or email
+func (gen *Generator) readContributors(credits []*commitCredits, previous string) ([]*types.Contributor, error) {
+	if previous != "" && !gen.authors.has(previous) {
+		if err := gen.readAuthorIndex([]string{previous}); err != nil {
+			return nil, err
+		}
+	}
+
+	contributors := make([]*types.Contributor, 0)
+	index := make(map[string]*types.Contributor)
+
+	get := func(c *types.CommitContributor) *types.Contributor {
+		contributor, ok := index[c.Key()]
+		if !ok {
+			contributor = &types.Contributor{
+				CommitContributor: c,
+			}
+			index[c.Key()] = contributor
+			contributors = append(contributors, contributor)
+		}
+		return contributor
+	}
+
+	for _, credit := range credits {
+		for _, c := range credit.authors {
+			get(c).Commits++
+		}
+		for _, c := range credit.reviewers {
+			get(c).Reviews++
+		}
+	}
+
+	for _, c := range contributors {
+		c.FirstTime = c.Commits > 0 && !gen.authors.known(previous, c.CommitContributor)
+	}
+
+	types.SortContributors(contributors)
+
+	return contributors, nil
+}
+
+// processContributors hides the identities of contributors if processor is an `IdentityProcessor`
+func processContributors(contributors []*types.Contributor, processor gitlib.Processor) {
+	p, ok := processor.(gitlib.IdentityProcessor)
+	if !ok {
+		return
+	}
+	for _, c := range contributors {
+		p.ProcessIdentity(c.CommitContributor)
+	}
+}
+
+// authorIndex records the identities of commits reachable from revs,
+// each call of `readAuthorIndex` adds a chain of revs sorted from oldest to newest
+type authorIndex struct {
+	// position of revs in their chain
+	revs map[string]authorIndexPos
+	// lower case name or email to the order of the oldest rev reaching it of each chain
+	since map[string]map[int]int
+	// count of chains
+	chains int
+}
+
+type authorIndexPos struct {
+	chain int
+	// the older rev is smaller in the chain
+	order int
+}
+
+func (idx *authorIndex) has(rev string) bool {
+	if idx == nil {
+		return false
+	}
+	_, ok := idx.revs[rev]
+	return ok
+}
+
+// known reports whether the contributor authored any commit reachable from rev
+func (idx *authorIndex) known(rev string, c *types.CommitContributor) bool {
+	if !idx.has(rev) {
+		return false
+	}
+	pos := idx.revs[rev]
+	for _, key := range []string{strings.ToLower(c.Name), strings.ToLower(c.Email)} {
+		if since, ok := idx.since[key][pos.chain]; ok && key != "" && since <= pos.order {
+			return true
+		}
+	}
+	return false
+}
+
+// readAuthorIndex adds the authors reachable from revs sorted from oldest to newest to the index,
+// the revs already indexed are skipped. Only the oldest new rev is read with full history,
+// each of the others reads the commits after its older neighbour
+func (gen *Generator) readAuthorIndex(revs []string) error {
+	if gen.authors == nil {
+		gen.authors = &authorIndex{
+			revs:  make(map[string]authorIndexPos),
+			since: make(map[string]map[int]int),
+		}
+	}
+	idx := gen.authors
+
+	chain := idx.chains
+	prev := ""
+	order := 0
+	for _, rev := range revs {
+		if idx.has(rev) {
+			continue
+		}
+
+		query := rev
+		if prev != "" {
+			query = prev + ".." + rev
+		}
+		authors, err := gen.commitParser.ParseAuthors(query)
+		if err != nil {
+			return err
+		}
+
+		idx.revs[rev] = authorIndexPos{chain: chain, order: order}
+		for _, author := range authors {
+			for _, key := range []string{strings.ToLower(author.Name), strings.ToLower(author.Email)} {
+				if idx.since[key] == nil {
+					idx.since[key] = make(map[int]int)
+				}
+				if _, ok := idx.since[key][chain]; !ok {
+					idx.since[key][chain] = order
+				}
+			}
+		}
+		prev = rev
+		order++
+	}
+	if order > 0 {
+		idx.chains++
+	}
+
+	return nil
+}
+
+// readUnreleased reads the commits after the latest tag, the contributors are read if `withContributors`
+func (gen *Generator) readUnreleased(tags []*types.Tag, processor gitlib.Processor, withContributors bool) (*types.Unreleased, error) {
 	if gen.config.Options.NextTag != "" {
 		return &types.Unreleased{}, nil
 	}
 
-	rev := "HEAD"
+	rev := gen.headRef()
 
 	if len(tags) > 0 {
-		rev = tags[0].Name + "..HEAD"
+		rev = tags[0].Name + ".." + gen.headRef()
 	}
 
-	commits, err := gen.commitParser.Parse(rev, processor)
+	commits, credits, err := gen.readCommits(rev, processor)
 	if err != nil {
 		return nil, err
 	}
 
 	commitGroups, mergeCommits, revertCommits, noteGroups := gen.commitExtractor.Extract(commits)
 
+	var contributors []*types.Contributor
+	if withContributors {
+		previous := ""
+		if len(tags) > 0 {
+			previous = tags[0].Name
+		}
+		contributors, err = gen.readContributors(credits, previous)
+		if err != nil {
+			return nil, errors.Wrap(err, "read contributors of unreleased")
+		}
+		processContributors(contributors, processor)
+	}
+
 	unreleased := &types.Unreleased{
 		CommitGroups:  commitGroups,
 		Commits:       commits,
 		MergeCommits:  mergeCommits,
 		RevertCommits: revertCommits,
 		NoteGroups:    noteGroups,
+		Contributors:  contributors,
+		Components:    gen.extractComponents(commits),
+		DiffStat:      gen.diffStat(commits),
 	}
 
 	return unreleased, nil
 }
 
+func (gen *Generator) diffStat(commits []*types.Commit) *types.DiffStat {
+	if !gen.config.Options.DiffStat {
+		return nil
+	}
+	return types.NewDiffStat(commits)
+}
+
+// extractComponents groups commits of each component, the commits not matched by any component are in `Others`
+func (gen *Generator) extractComponents(commits []*types.Commit) []*types.ComponentGroup {
+	if len(gen.config.Components) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(gen.config.Components)+1)
+	for _, component := range gen.config.Components {
+		names = append(names, component.Name)
+	}
+	names = append(names, types.ComponentOthers)
+
+	componentCommits := make(map[string][]*types.Commit)
+	for _, commit := range commits {
+		if len(commit.Components) == 0 {
+			componentCommits[types.ComponentOthers] = append(componentCommits[types.ComponentOthers], commit)
+		}
+		for _, name := range commit.Components {
+			componentCommits[name] = append(componentCommits[name], commit)
+		}
+	}
+
+	ret := make([]*types.ComponentGroup, 0)
+	for _, name := range names {
+		commits, ok := componentCommits[name]
+		if !ok {
+			continue
+		}
+		commitGroups, _, _, _ := gen.commitExtractor.Extract(commits)
+		if len(commitGroups) == 0 {
+			continue
+		}
+		ret = append(ret, &types.ComponentGroup{
+			Name:         name,
+			CommitGroups: commitGroups,
+			Commits:      commits,
+		})
+	}
+
+	return ret
+}
+
 func (gen *Generator) getTags(query string) ([]*types.Tag, string, error) {
 	tags, err := gen.tagReader.ReadAll()
 	if err != nil {
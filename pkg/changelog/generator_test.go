@@ -1,10 +1,16 @@
 package changelog
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
+	gitcmd "github.com/tsuyoshiwada/go-gitcmd"
 
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/gitlib"
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
@@ -45,4 +51,388 @@ func TestFilterTagsByPrefix(t *testing.T) {
 	assert.Equal(tags, filterTagsByPrefix("3.4", tags))
 
 	assert.Equal(make([]*types.Tag, 0), filterTagsByPrefix("3.3", tags))
+
+	// v3.40.x is not in release 3.4
+	assert.Equal(tags, filterTagsByPrefix("3.4", append([]*types.Tag{{Name: "v3.40.0"}}, tags...)))
+}
+
+type fakeCommitParser struct {
+	gitlib.CommitParser
+	authors map[string][]*types.CommitContributor
+	commits map[string][]*types.Commit
+	queries []string
+}
+
+func (p *fakeCommitParser) ParseAuthors(rev string) ([]*types.CommitContributor, error) {
+	p.queries = append(p.queries, rev)
+	return p.authors[rev], nil
+}
+
+func (p *fakeCommitParser) Parse(rev string, processor gitlib.Processor) ([]*types.Commit, error) {
+	return p.commits[rev], nil
+}
+
+func TestReadContributors(t *testing.T) {
+	assert := assert.New(t)
+
+	gen := &Generator{
+		commitParser: &fakeCommitParser{
+			authors: map[string][]*types.CommitContributor{
+				"v3.4.0": {
+					{Name: "foo", Email: "foo@example.com"},
+					{Name: "Bar", Email: "bar@old.example.com"},
+				},
+			},
+		},
+	}
+
+	commits := []*types.Commit{
+		{
+			Author: &types.CommitAuthor{Name: "foo", Email: "foo@example.com"},
+			CoAuthors: []*types.CommitContributor{
+				{Name: "hoge", Email: "hoge@example.com"},
+			},
+		},
+		{
+			Author: &types.CommitAuthor{Name: "bar", Email: "bar@example.com"},
+			ReviewedBy: []*types.CommitContributor{
+				{Name: "foo", Email: "foo@example.com"},
+				{Name: "fuga", Email: "fuga@example.com"},
+			},
+		},
+		{
+			Author: &types.CommitAuthor{Name: "hoge", Email: "hoge@example.com"},
+		},
+		{
+			Author: &types.CommitAuthor{Name: "hoge", Email: "hoge@example.com"},
+		},
+	}
+
+	credits := make([]*commitCredits, len(commits))
+	for i, commit := range commits {
+		credits[i] = newCommitCredits(commit)
+	}
+
+	contributors, err := gen.readContributors(credits, "v3.4.0")
+	assert.Nil(err)
+	assert.Equal([]*types.Contributor{
+		{
+			CommitContributor: &types.CommitContributor{Name: "hoge", Email: "hoge@example.com"},
+			Commits:           3,
+			FirstTime:         true,
+		},
+		{
+			CommitContributor: &types.CommitContributor{Name: "foo", Email: "foo@example.com"},
+			Commits:           1,
+			Reviews:           1,
+			FirstTime:         false,
+		},
+		{
+			CommitContributor: &types.CommitContributor{Name: "bar", Email: "bar@example.com"},
+			Commits:           1,
+			FirstTime:         false,
+		},
+		{
+			CommitContributor: &types.CommitContributor{Name: "fuga", Email: "fuga@example.com"},
+			Reviews:           1,
+			FirstTime:         false,
+		},
+	}, contributors)
+
+	contributors, err = gen.readContributors(credits, "")
+	assert.Nil(err)
+	for _, c := range contributors {
+		assert.Equal(c.Commits > 0, c.FirstTime, c.Name)
+	}
+}
+
+func TestReadAuthorIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &fakeCommitParser{
+		authors: map[string][]*types.CommitContributor{
+			"v3.3.0": {
+				{Name: "foo", Email: "foo@example.com"},
+			},
+			"v3.3.0..v3.4.0": {
+				{Name: "bar", Email: "bar@example.com"},
+			},
+		},
+	}
+	gen := &Generator{
+		commitParser: parser,
+	}
+
+	assert.Nil(gen.readAuthorIndex([]string{"v3.3.0", "v3.4.0"}))
+	assert.Equal([]string{"v3.3.0", "v3.3.0..v3.4.0"}, parser.queries)
+
+	foo := &types.CommitContributor{Name: "Foo", Email: "foo@example.com"}
+	bar := &types.CommitContributor{Name: "bar", Email: "bar@example.com"}
+	assert.True(gen.authors.known("v3.3.0", foo))
+	assert.False(gen.authors.known("v3.3.0", bar))
+	assert.True(gen.authors.known("v3.4.0", foo))
+	assert.True(gen.authors.known("v3.4.0", bar))
+	assert.False(gen.authors.known("v3.5.0", foo))
+
+	// the index is reused by contributors of versions
+	_, err := gen.readContributors(nil, "v3.4.0")
+	assert.Nil(err)
+	assert.Len(parser.queries, 2)
+
+	// the rev not indexed is added without dropping the others
+	parser.authors["v3.2.0"] = []*types.CommitContributor{{Name: "bar", Email: "bar@example.com"}}
+	_, err = gen.readContributors(nil, "v3.2.0")
+	assert.Nil(err)
+	assert.Equal([]string{"v3.3.0", "v3.3.0..v3.4.0", "v3.2.0"}, parser.queries)
+	assert.True(gen.authors.known("v3.2.0", bar))
+	assert.False(gen.authors.known("v3.2.0", foo))
+	assert.False(gen.authors.known("v3.3.0", bar))
+	assert.True(gen.authors.known("v3.4.0", bar))
+}
+
+func TestReadUnreleasedWithoutContributors(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &fakeCommitParser{
+		commits: map[string][]*types.Commit{
+			"v3.4.0..HEAD": {
+				{Type: "fix", Author: &types.CommitAuthor{Name: "foo", Email: "foo@example.com"}},
+			},
+		},
+	}
+	gen := &Generator{
+		config: &types.ChangelogConfig{
+			Options: &types.ChangelogConfigOptions{},
+		},
+		commitParser:    parser,
+		commitExtractor: gitlib.NewCommitExtractor(&types.ChangelogConfigOptions{}),
+	}
+
+	// next version does not read the author history
+	unreleased, err := gen.readUnreleased([]*types.Tag{{Name: "v3.4.0"}}, nil, false)
+	assert.Nil(err)
+	assert.Len(unreleased.Commits, 1)
+	assert.Nil(unreleased.Contributors)
+	assert.Len(parser.queries, 0)
+}
+
+func TestReadContributorsPrivacy(t *testing.T) {
+	assert := assert.New(t)
+
+	gen := &Generator{
+		commitParser: &fakeCommitParser{
+			authors: map[string][]*types.CommitContributor{
+				"v3.4.0": {
+					{Name: "foo", Email: "foo@example.com"},
+				},
+			},
+			commits: map[string][]*types.Commit{
+				"v3.4.0..HEAD": {
+					{
+						Author: &types.CommitAuthor{Name: "Foo Bar", Email: "foo@example.com"},
+						CoAuthors: []*types.CommitContributor{
+							{Name: "hoge", Email: "hoge@example.com"},
+						},
+					},
+				},
+			},
+		},
+		commitExtractor: gitlib.NewCommitExtractor(&types.ChangelogConfigOptions{}),
+	}
+	processor := gitlib.NewPrivacyProcessor(&types.GlobalChangelogOutConfig{
+		EmailPolicy: types.EmailPolicyObfuscate,
+	})
+	processor.Bootstrap(&types.ChangelogConfig{})
+
+	commits, credits, err := gen.readCommits("v3.4.0..HEAD", processor)
+	assert.Nil(err)
+	assert.Equal("foo [at] example [dot] com", commits[0].Author.Email)
+
+	contributors, err := gen.readContributors(credits, "v3.4.0")
+	assert.Nil(err)
+	processContributors(contributors, processor)
+	assert.Equal([]*types.Contributor{
+		{
+			CommitContributor: &types.CommitContributor{Name: "Foo Bar", Email: "foo [at] example [dot] com"},
+			Commits:           1,
+			FirstTime:         false,
+		},
+		{
+			CommitContributor: &types.CommitContributor{Name: "hoge", Email: "hoge [at] example [dot] com"},
+			Commits:           1,
+			FirstTime:         true,
+		},
+	}, contributors)
+}
+
+func TestReadCommitsFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := &types.ChangelogConfigOptions{
+		Filter: &types.CommitFilterConfig{
+			Exclude: []string{`author.email =~ "bot@"`},
+		},
+	}
+	gen := &Generator{
+		commitParser: &fakeCommitParser{
+			commits: map[string][]*types.Commit{
+				"v3.4.0..HEAD": {
+					{Type: "feat", Author: &types.CommitAuthor{Name: "foo", Email: "foo@example.com"}},
+					{Type: "chore", Author: &types.CommitAuthor{Name: "bot", Email: "bot@example.com"}},
+				},
+			},
+		},
+		commitExtractor: gitlib.NewCommitExtractor(opts),
+	}
+
+	// excluded commits are dropped before commits, credits and stats are built
+	commits, credits, err := gen.readCommits("v3.4.0..HEAD", nil)
+	assert.Nil(err)
+	assert.Len(commits, 1)
+	assert.Equal("foo", commits[0].Author.Name)
+	assert.Len(credits, 1)
+	assert.Equal("minor", GetBumpLevel(commits, map[string]string{"feat": "minor", "chore": "major"}))
+}
+
+type fakeGitClient struct {
+	gitcmd.Client
+	exec func(subcmd string, args ...string) (string, error)
+}
+
+func (c *fakeGitClient) Exec(subcmd string, args ...string) (string, error) {
+	return c.exec(subcmd, args...)
+}
+
+type fakeTagReader struct {
+	tags []*types.Tag
+}
+
+func (r *fakeTagReader) ReadAll() ([]*types.Tag, error) {
+	return r.tags, nil
+}
+
+func TestVersionBase(t *testing.T) {
+	assert := assert.New(t)
+
+	newTag := func(name string) *types.Tag {
+		ver := semver.MustParse(strings.TrimPrefix(name, "v"))
+		return &types.Tag{Name: name, Version: &ver}
+	}
+
+	tagReader := &fakeTagReader{
+		tags: []*types.Tag{newTag("v3.6.1"), newTag("v3.6.0"), newTag("v3.5.12"), newTag("v3.5.3"), newTag("v3.4.0")},
+	}
+	branches := "refs/remotes/origin/master\nrefs/remotes/origin/release/3.5\nrefs/remotes/origin/release/3.6\n"
+	gen := &Generator{
+		config: &types.ChangelogConfig{
+			Options: &types.ChangelogConfigOptions{},
+		},
+		tagReader: tagReader,
+		client: &fakeGitClient{
+			exec: func(subcmd string, args ...string) (string, error) {
+				switch {
+				case subcmd == "merge-base" && args[0] == "v3.5.12" && args[1] == "v3.6.0":
+					return "forkpoint\n", nil
+				case subcmd == "merge-base" && args[0] == "refs/remotes/origin/release/3.5" && args[1] == "v3.6.0":
+					return "branchpoint\n", nil
+				case subcmd == "for-each-ref":
+					return branches, nil
+				case subcmd == "rev-parse" && args[len(args)-1] == "refs/remotes/origin/master^{commit}":
+					return "masterhash\n", nil
+				}
+				return "", errors.Error("exit status 1")
+			},
+		},
+	}
+
+	// the same release line
+	base, err := gen.versionBase(newTag("v3.6.1"), "v3.6.0")
+	assert.Nil(err)
+	assert.Equal("v3.6.0", base)
+
+	// previous is a commit
+	base, err = gen.versionBase(newTag("v3.6.1"), "abc123")
+	assert.Nil(err)
+	assert.Equal("abc123", base)
+
+	// fork point with the newest tag of previous release line
+	base, err = gen.versionBase(newTag("v3.6.0"), "v3.5.12")
+	assert.Nil(err)
+	assert.Equal("forkpoint", base)
+	base, err = gen.versionBase(newTag("v3.6.0"), "v3.5.3")
+	assert.Nil(err)
+	assert.Equal("forkpoint", base)
+
+	// the first tag of release branch, e.g. tags are filtered by branch
+	base, err = gen.versionBase(newTag("v3.6.0"), "")
+	assert.Nil(err)
+	assert.Equal("forkpoint", base)
+
+	// fork point with the previous release branch if it has no tags
+	tagReader.tags = []*types.Tag{newTag("v3.6.0")}
+	base, err = gen.versionBase(newTag("v3.6.0"), "")
+	assert.Nil(err)
+	assert.Equal("branchpoint", base)
+
+	// the first release line of repo
+	branches = "refs/remotes/origin/master\nrefs/remotes/origin/release/3.6\n"
+	base, err = gen.versionBase(newTag("v3.6.0"), "")
+	assert.Nil(err)
+	assert.Equal("", base)
+
+	// base ref takes precedence over fork point
+	gen.config.BaseRef = "master"
+	base, err = gen.versionBase(newTag("v3.6.0"), "v3.5.12")
+	assert.Nil(err)
+	assert.Equal("masterhash", base)
+	base, err = gen.versionBase(newTag("v3.6.0"), "")
+	assert.Nil(err)
+	assert.Equal("masterhash", base)
+	base, err = gen.versionBase(newTag("v3.6.1"), "v3.6.0")
+	assert.Nil(err)
+	assert.Equal("v3.6.0", base)
+
+	gen.config.BaseRef = "unknown"
+	_, err = gen.versionBase(newTag("v3.6.0"), "v3.5.12")
+	assert.NotNil(err)
+}
+
+func TestExtractComponents(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := &types.ChangelogConfigOptions{
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "Title",
+		CommitSortBy:      "Scope",
+	}
+	gen := &Generator{
+		config: &types.ChangelogConfig{
+			Components: []*types.ComponentConfig{
+				{Name: "compute", Paths: []string{"pkg/compute"}},
+				{Name: "host", Paths: []string{"pkg/hostman"}},
+				{Name: "web", Paths: []string{"web"}},
+			},
+			Options: opts,
+		},
+		commitExtractor: gitlib.NewCommitExtractor(opts),
+	}
+
+	commits := []*types.Commit{
+		{Type: "feat", Subject: "1", Components: []string{"web", "host"}},
+		{Type: "fix", Subject: "2", Components: []string{"host"}},
+		{Type: "docs", Subject: "3", Components: []string{}},
+	}
+
+	components := gen.extractComponents(commits)
+	assert.Len(components, 3)
+	assert.Equal("host", components[0].Name)
+	assert.Equal([]*types.Commit{commits[0], commits[1]}, components[0].Commits)
+	assert.Len(components[0].CommitGroups, 2)
+	assert.Equal("web", components[1].Name)
+	assert.Equal([]*types.Commit{commits[0]}, components[1].Commits)
+	assert.Equal(types.ComponentOthers, components[2].Name)
+	assert.Equal([]*types.Commit{commits[2]}, components[2].Commits)
+
+	gen.config.Components = nil
+	assert.Nil(gen.extractComponents(commits))
 }
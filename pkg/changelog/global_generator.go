@@ -4,16 +4,22 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"text/template"
 
+	"github.com/blang/semver/v4"
+
+	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"github.com/yunionio/git-tools/pkg/gitlib"
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
+const (
+	// semverWeightBase is the weight base of each version component
+	semverWeightBase = 1000
+)
+
 type GlobalGenerator struct {
 	config    *types.GlobalChangeLogConfig
 	processor gitlib.Processor
@@ -28,49 +34,88 @@ func NewGlobalGenerator(config *types.GlobalChangeLogConfig) *GlobalGenerator {
 
 func (gen *GlobalGenerator) GetResults() (*types.GlobalChangeLogResult, error) {
 	ret := &types.GlobalChangeLogResult{
-		Releases: make([]*types.ReleaseChangeLogResult, len(gen.config.Releases)),
+		Releases: make([]*types.ReleaseChangeLogResult, 0, len(gen.config.Releases)),
+		Failures: make([]*types.RepoFailure, 0),
 	}
 
-	for idx, rls := range gen.config.Releases {
+	for _, rls := range gen.config.Releases {
 		rRet, err := gen.GetReleaseResults(rls)
 		if err != nil {
-			return nil, errors.Wrapf(err, "get release results")
+			if !gen.config.KeepGoing {
+				return nil, errors.Wrapf(err, "get release results")
+			}
+			ret.Failures = append(ret.Failures, newRepoFailure(rls.Branch, "", err))
+			continue
 		}
 
-		ret.Releases[idx] = rRet
+		ret.Releases = append(ret.Releases, rRet)
+		ret.Failures = append(ret.Failures, rRet.Failures...)
 	}
 
 	return ret, nil
 }
 
+func newRepoFailure(branch string, repo string, err error) *types.RepoFailure {
+	log.Warningf("skip failed release %q repo %q: %v", branch, repo, err)
+	return &types.RepoFailure{
+		Branch: branch,
+		Repo:   repo,
+		Error:  err.Error(),
+	}
+}
+
 func (gen *GlobalGenerator) getProcesser(repo *types.Repository) gitlib.Processor {
 	// TODO: support others
-	return &gitlib.GitHubProcessor{}
+	var processor gitlib.Processor = &gitlib.GitHubProcessor{}
+
+	// hide personal data before adding links
+	if privacy := gitlib.NewPrivacyProcessor(gen.config.Output); privacy != nil {
+		processor = gitlib.ChainProcessor{privacy, processor}
+	}
+
+	return processor
 }
 
-func GetBranchWeight(branch string) (int, error) {
-	verStr, err := GetSemverBranchVersion(branch)
+func GetBranchWeight(branch string, patterns ...string) (int, error) {
+	verStr, err := GetSemverBranchVersion(branch, patterns...)
 	if err != nil {
 		return 0, errors.Wrapf(err, "GetSemverBranchVersion %q", branch)
 	}
 	return GetSemverStrWeight(verStr)
 }
 
+// GetSemverStrWeight returns the weight of version string, `major.minor` is treated as `major.minor.0`
 func GetSemverStrWeight(verStr string) (int, error) {
-	verStr = strings.ReplaceAll(verStr, ".", "")
-	weight, err := strconv.Atoi(verStr)
+	ver, err := semver.ParseTolerant(verStr)
 	if err != nil {
 		return 0, errors.Wrapf(err, "parse sem version string %q", verStr)
 	}
-	return weight, nil
+	if len(ver.Pre) > 0 || len(ver.Build) > 0 {
+		return 0, errors.Errorf("sem version string %q is not a release version", verStr)
+	}
+	return GetSemverWeight(ver)
+}
+
+// GetSemverWeight returns `major*1000000 + minor*1000 + patch`,
+// the order of weights is the same as versions if each component is less than 1000
+func GetSemverWeight(ver semver.Version) (int, error) {
+	for _, v := range []uint64{ver.Major, ver.Minor, ver.Patch} {
+		if v >= semverWeightBase {
+			return 0, errors.Errorf("version %q component %d exceeds %d", ver, v, semverWeightBase-1)
+		}
+	}
+	weight := (ver.Major*semverWeightBase+ver.Minor)*semverWeightBase + ver.Patch
+	return int(weight), nil
 }
 
 func (gen *GlobalGenerator) GetReleaseResults(rls *types.ReleaseChangeLogConfig) (*types.ReleaseChangeLogResult, error) {
 	ret := &types.ReleaseChangeLogResult{
-		Branch: rls.Branch,
-		Repos:  make([]*types.RepoChangelogResult, len(rls.Repos)),
+		Branch:   rls.Branch,
+		Repos:    make([]*types.RepoChangelogResult, 0, len(rls.Repos)),
+		NextTag:  rls.NextTag,
+		Failures: make([]*types.RepoFailure, 0),
 	}
-	branchWeight, err := GetBranchWeight(rls.Branch)
+	branchWeight, err := GetBranchWeight(rls.Branch, gen.config.Options.ReleaseBranchPatterns...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "GetBranchWeight %q", rls.Branch)
 	}
@@ -78,19 +123,28 @@ func (gen *GlobalGenerator) GetReleaseResults(rls *types.ReleaseChangeLogConfig)
 
 	for idx := range rls.Repos {
 		repo := rls.Repos[idx]
-		conf := rls.ToChangelogConfig(gen.config.Bin, gen.config.Options, idx)
+		conf := gen.config.ToChangelogConfig(*rls, idx)
 
-		gen := NewGenerator(conf, gen.getProcesser(repo))
-		unreleased, versions, err := gen.GetSemverBranchResults(rls.Branch)
+		repoGen := NewGenerator(conf, gen.getProcesser(repo))
+		unreleased, versions, err := repoGen.GetSemverBranchResults(conf.Branch)
 		if err != nil {
-			return nil, errors.Wrapf(err, "get results for branch %q", rls.Branch)
+			err = errors.Wrapf(err, "get results for branch %q", rls.Branch)
+			if !gen.config.KeepGoing {
+				return nil, err
+			}
+			failure := newRepoFailure(rls.Branch, repo.Name, err)
+			if failure.Versions, err = repoGen.GetSemverBranchVersions(conf.Branch); err != nil {
+				log.Warningf("read versions of failed repo %q: %v", repo.Name, err)
+			}
+			ret.Failures = append(ret.Failures, failure)
+			continue
 		}
 		repoRet := &types.RepoChangelogResult{
 			Repo:       repo,
 			Versions:   versions,
 			Unreleased: unreleased,
 		}
-		ret.Repos[idx] = repoRet
+		ret.Repos = append(ret.Repos, repoRet)
 	}
 
 	return ret, nil
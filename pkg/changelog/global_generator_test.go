@@ -1,6 +1,13 @@
 package changelog
 
-import "testing"
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
 
 func TestGetSemverStrWeight(t *testing.T) {
 	tests := []struct {
@@ -12,13 +19,13 @@ func TestGetSemverStrWeight(t *testing.T) {
 		{
 			name:    "3.4.1",
 			verStr:  "3.4.1",
-			want:    341,
+			want:    3004001,
 			wantErr: false,
 		},
 		{
 			name:    "3.4",
 			verStr:  "3.4",
-			want:    34,
+			want:    3004000,
 			wantErr: false,
 		},
 		{
@@ -27,6 +34,12 @@ func TestGetSemverStrWeight(t *testing.T) {
 			want:    0,
 			wantErr: true,
 		},
+		{
+			name:    "3.4.1000",
+			verStr:  "3.4.1000",
+			want:    0,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -41,3 +54,66 @@ func TestGetSemverStrWeight(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSemverStrWeightOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	// sorted from the oldest
+	versions := []string{"3.1.9", "3.4.0", "3.4.2", "3.4.10", "3.5.0", "3.9.9", "3.10.0", "3.10.1", "31.0.0"}
+	weights := make([]int, len(versions))
+	for i, ver := range versions {
+		weight, err := GetSemverStrWeight(ver)
+		assert.Nil(err)
+		weights[i] = weight
+	}
+	assert.True(sort.IntsAreSorted(weights), "%v", weights)
+
+	for i := 1; i < len(weights); i++ {
+		assert.NotEqual(weights[i-1], weights[i])
+	}
+
+	// branch weights
+	branches := []string{"release/3.4", "release/3.9", "release/3.10", "release/31.0"}
+	weights = make([]int, len(branches))
+	for i, branch := range branches {
+		weight, err := GetBranchWeight(branch)
+		assert.Nil(err)
+		weights[i] = weight
+	}
+	assert.True(sort.IntsAreSorted(weights), "%v", weights)
+}
+
+func TestGlobalGeneratorKeepGoing(t *testing.T) {
+	assert := assert.New(t)
+
+	config := &types.GlobalChangeLogConfig{
+		Bin:     "git",
+		Options: &types.ChangelogConfigOptions{},
+		Releases: []*types.ReleaseChangeLogConfig{
+			{
+				Branch: "release/3.4",
+				Repos: []*types.Repository{
+					{Name: "missing", WorkingDir: "/nonexistent/missing"},
+				},
+			},
+			{
+				Branch: "master",
+			},
+		},
+	}
+
+	_, err := NewGlobalGenerator(config).GetResults()
+	assert.NotNil(err)
+
+	config.KeepGoing = true
+	ret, err := NewGlobalGenerator(config).GetResults()
+	assert.Nil(err)
+	assert.Len(ret.Releases, 1)
+	assert.Len(ret.Releases[0].Repos, 0)
+	assert.Len(ret.Failures, 2)
+	assert.Equal("release/3.4", ret.Failures[0].Branch)
+	assert.Equal("missing", ret.Failures[0].Repo)
+	assert.Equal("master", ret.Failures[1].Branch)
+	assert.Equal("", ret.Failures[1].Repo)
+	assert.Equal(ret.Failures[:1], ret.Releases[0].Failures)
+}
@@ -0,0 +1,168 @@
+package changelog
+
+import (
+	"github.com/blang/semver/v4"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+var (
+	defaultBumpRules = map[string]string{
+		"feat":  types.BumpMinor,
+		"docs":  types.BumpNone,
+		"chore": types.BumpNone,
+		"ci":    types.BumpNone,
+		"test":  types.BumpNone,
+	}
+)
+
+// GetBumpLevel computes the bump level of commits by rules merged over the default ones,
+// commits with breaking change notes bump major and the ones not in rules bump patch
+func GetBumpLevel(commits []*types.Commit, rules map[string]string) string {
+	merged := make(map[string]string, len(defaultBumpRules)+len(rules))
+	for typ, l := range defaultBumpRules {
+		merged[typ] = l
+	}
+	for typ, l := range rules {
+		merged[typ] = l
+	}
+
+	level := types.BumpNone
+	for _, commit := range commits {
+		cLevel := types.BumpPatch
+		if isBreakingChange(commit) {
+			cLevel = types.BumpMajor
+		} else if l, ok := merged[commit.Type]; ok {
+			cLevel = l
+		}
+		if types.BumpLevelWeight(cLevel) > types.BumpLevelWeight(level) {
+			level = cLevel
+		}
+	}
+
+	return level
+}
+
+// BumpVersion increases the version by level
+func BumpVersion(ver semver.Version, level string) semver.Version {
+	next := semver.Version{
+		Major: ver.Major,
+		Minor: ver.Minor,
+		Patch: ver.Patch,
+	}
+	switch level {
+	case types.BumpMajor:
+		next.Major++
+		next.Minor = 0
+		next.Patch = 0
+	case types.BumpMinor:
+		next.Minor++
+		next.Patch = 0
+	case types.BumpPatch:
+		next.Patch++
+	default:
+		return ver
+	}
+	return next
+}
+
+// GetNextVersions proposes the next version of each release branch by unreleased commits.
+// The version stays on the release line (only patch is bumped) if `keepBranch` is true
+func (gen *GlobalGenerator) GetNextVersions(keepBranch bool) (*types.GlobalNextVersionResult, error) {
+	ret := &types.GlobalNextVersionResult{
+		Releases: make([]*types.ReleaseNextVersion, len(gen.config.Releases)),
+	}
+
+	for idx, rls := range gen.config.Releases {
+		rRet, err := gen.GetReleaseNextVersion(rls, keepBranch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get release next version of branch %q", rls.Branch)
+		}
+		ret.Releases[idx] = rRet
+	}
+
+	return ret, nil
+}
+
+func (gen *GlobalGenerator) GetReleaseNextVersion(rls *types.ReleaseChangeLogConfig, keepBranch bool) (*types.ReleaseNextVersion, error) {
+	ret := &types.ReleaseNextVersion{
+		Branch:   rls.Branch,
+		Repos:    make([]*types.RepoNextVersion, 0, len(rls.Repos)),
+		Failures: make([]*types.RepoFailure, 0),
+	}
+
+	var (
+		newest *semver.Version
+		level  = types.BumpNone
+	)
+	for idx, repo := range rls.Repos {
+		conf := gen.config.ToChangelogConfig(*rls, idx)
+		tag, unreleased, err := NewGenerator(conf, nil).GetSemverBranchUnreleased(conf.Branch)
+		if err == nil && tag.Version == nil {
+			err = errors.Errorf("tag %q is not semantic version", tag.Name)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "get unreleased commits of repo %q", repo.Name)
+			if !gen.config.KeepGoing {
+				return nil, err
+			}
+			ret.Failures = append(ret.Failures, newRepoFailure(rls.Branch, repo.Name, err))
+			continue
+		}
+
+		repoRet := newRepoNextVersion(repo.Name, tag, unreleased, gen.config.Options.BumpRules, keepBranch)
+		ret.Repos = append(ret.Repos, repoRet)
+
+		if newest == nil || tag.Version.GT(*newest) {
+			newest = tag.Version
+		}
+		if types.BumpLevelWeight(repoRet.Bump) > types.BumpLevelWeight(level) {
+			level = repoRet.Bump
+		}
+	}
+
+	ret.Next = nextReleaseVersion(newest, level, keepBranch)
+
+	return ret, nil
+}
+
+// nextReleaseVersion bumps the newest released version of repos by the highest level,
+// so that the next version is not released by any repo. It's empty if there is nothing to release
+func nextReleaseVersion(newest *semver.Version, level string, keepBranch bool) string {
+	if newest == nil || level == types.BumpNone {
+		return ""
+	}
+	return "v" + BumpVersion(*newest, branchBumpLevel(level, keepBranch)).String()
+}
+
+// branchBumpLevel limits the level to patch to keep the version on release branch
+func branchBumpLevel(level string, keepBranch bool) string {
+	if keepBranch && types.BumpLevelWeight(level) > types.BumpLevelWeight(types.BumpPatch) {
+		return types.BumpPatch
+	}
+	return level
+}
+
+// newRepoNextVersion bumps the latest tag of repo by unreleased commits, `Next` is empty if there is nothing to release
+func newRepoNextVersion(repo string, tag *types.Tag, unreleased *types.Unreleased, rules map[string]string, keepBranch bool) *types.RepoNextVersion {
+	level := GetBumpLevel(unreleased.Commits, rules)
+	ret := &types.RepoNextVersion{
+		Repo:    repo,
+		Current: tag.Name,
+		Bump:    level,
+		Commits: len(unreleased.Commits),
+	}
+	for _, commit := range unreleased.Commits {
+		if isBreakingChange(commit) {
+			ret.BreakingChanges++
+		}
+	}
+
+	if level != types.BumpNone {
+		ret.Next = "v" + BumpVersion(*tag.Version, branchBumpLevel(level, keepBranch)).String()
+	}
+
+	return ret
+}
@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestGetBumpLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	fix := &types.Commit{Type: "fix"}
+	feat := &types.Commit{Type: "feat"}
+	perf := &types.Commit{Type: "perf"}
+	breaking := &types.Commit{
+		Type: "fix",
+		Notes: []*types.CommitNote{
+			{Title: "BREAKING CHANGE", Body: "api changed"},
+		},
+	}
+
+	assert.Equal(types.BumpNone, GetBumpLevel(nil, nil))
+	assert.Equal(types.BumpPatch, GetBumpLevel([]*types.Commit{fix, perf}, nil))
+	assert.Equal(types.BumpMinor, GetBumpLevel([]*types.Commit{fix, feat}, nil))
+	assert.Equal(types.BumpMajor, GetBumpLevel([]*types.Commit{feat, breaking}, nil))
+	assert.Equal(types.BumpPatch, GetBumpLevel([]*types.Commit{fix, feat}, map[string]string{"feat": types.BumpPatch}))
+	assert.Equal(types.BumpMinor, GetBumpLevel([]*types.Commit{fix, perf}, map[string]string{"perf": types.BumpMinor}))
+
+	// docs-only branch is not bumped unless the rule says so
+	docs := &types.Commit{Type: "docs"}
+	ci := &types.Commit{Type: "ci"}
+	assert.Equal(types.BumpNone, GetBumpLevel([]*types.Commit{docs, ci}, nil))
+	assert.Equal(types.BumpPatch, GetBumpLevel([]*types.Commit{docs, ci}, map[string]string{"docs": types.BumpPatch}))
+	assert.Equal(types.BumpNone, GetBumpLevel([]*types.Commit{fix, docs}, map[string]string{"fix": types.BumpNone}))
+
+	// partial rules are merged over the defaults
+	assert.Equal(types.BumpMinor, GetBumpLevel([]*types.Commit{fix, feat}, map[string]string{"fix": types.BumpPatch}))
+	assert.Equal(types.BumpMinor, GetBumpLevel([]*types.Commit{perf, docs}, map[string]string{"perf": types.BumpMinor}))
+}
+
+func TestValidateBumpRules(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil((&types.ChangelogConfigOptions{BumpRules: map[string]string{"refactor": types.BumpNone}}).Validate())
+	assert.NotNil((&types.ChangelogConfigOptions{BumpRules: map[string]string{"refactor": "skip"}}).Validate())
+}
+
+func TestBumpVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	ver := semver.MustParse("3.4.10")
+	assert.Equal("3.4.10", BumpVersion(ver, types.BumpNone).String())
+	assert.Equal("3.4.11", BumpVersion(ver, types.BumpPatch).String())
+	assert.Equal("3.5.0", BumpVersion(ver, types.BumpMinor).String())
+	assert.Equal("4.0.0", BumpVersion(ver, types.BumpMajor).String())
+}
+
+func TestNewRepoNextVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	ver := semver.MustParse("3.4.10")
+	tag := &types.Tag{Name: "v3.4.10", Version: &ver}
+
+	// nothing to release
+	assert.Equal(&types.RepoNextVersion{
+		Repo:    "cloudpods",
+		Current: "v3.4.10",
+		Bump:    types.BumpNone,
+	}, newRepoNextVersion("cloudpods", tag, &types.Unreleased{}, nil, true))
+
+	unreleased := &types.Unreleased{
+		Commits: []*types.Commit{{Type: "fix"}, {Type: "feat"}},
+	}
+	ret := newRepoNextVersion("cloudpods", tag, unreleased, nil, true)
+	assert.Equal("v3.4.11", ret.Next)
+	assert.Equal(types.BumpMinor, ret.Bump)
+	assert.Equal(2, ret.Commits)
+
+	assert.Equal("v3.5.0", newRepoNextVersion("cloudpods", tag, unreleased, nil, false).Next)
+}
+
+func TestNextReleaseVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	newest := semver.MustParse("3.4.10")
+	assert.Equal("", nextReleaseVersion(nil, types.BumpPatch, true))
+	assert.Equal("", nextReleaseVersion(&newest, types.BumpNone, true))
+	assert.Equal("v3.4.11", nextReleaseVersion(&newest, types.BumpPatch, true))
+	assert.Equal("v3.4.11", nextReleaseVersion(&newest, types.BumpMajor, true))
+	assert.Equal("v4.0.0", nextReleaseVersion(&newest, types.BumpMajor, false))
+}
@@ -0,0 +1,124 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+const (
+	releaseNoteExt       = ".md"
+	releaseNoteTagPrefix = "v"
+	frontMatterDelimiter = "---"
+)
+
+// LoadReleaseNotes reads `<dir>/<branch>/<version>.md` files keyed by semantic version,
+// the version of file name may have `v` prefix. A missing branch dir is treated as no notes
+func LoadReleaseNotes(dir string, branch string) (map[string]*types.ReleaseNote, error) {
+	notes := make(map[string]*types.ReleaseNote)
+
+	branchDir := filepath.Join(dir, filepath.FromSlash(branch))
+	files, err := ioutil.ReadDir(branchDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return notes, nil
+		}
+		return nil, errors.Wrapf(err, "read release notes dir %q", branchDir)
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != releaseNoteExt {
+			continue
+		}
+		fileName := filepath.Join(branchDir, f.Name())
+		verStr := strings.TrimPrefix(strings.TrimSuffix(f.Name(), releaseNoteExt), releaseNoteTagPrefix)
+		ver, err := semver.Parse(verStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid version of release note %q", fileName)
+		}
+		if _, ok := notes[ver.String()]; ok {
+			return nil, errors.Errorf("duplicated release note %q of version %s", fileName, ver)
+		}
+
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read release note %q", fileName)
+		}
+		note, err := ParseReleaseNote(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse release note %q", fileName)
+		}
+		notes[ver.String()] = note
+	}
+
+	return notes, nil
+}
+
+// ParseReleaseNote parses the yaml front matter and markdown content
+func ParseReleaseNote(content string) (*types.ReleaseNote, error) {
+	note := new(types.ReleaseNote)
+
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		note.Content = strings.TrimSpace(content)
+		return note, nil
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, errors.Errorf("front matter is not closed by %q", frontMatterDelimiter)
+	}
+
+	frontMatter := strings.Join(lines[1:end], "\n")
+	if strings.TrimSpace(frontMatter) != "" {
+		obj, err := jsonutils.ParseYAML(frontMatter)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse front matter")
+		}
+		if err := obj.Unmarshal(note); err != nil {
+			return nil, errors.Wrap(err, "unmarshal front matter")
+		}
+	}
+	note.Content = strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+
+	return note, nil
+}
+
+// MergeReleaseNotes merges notes into versions of release,
+// the versions of notes not found in release are returned
+func MergeReleaseNotes(data *types.ReleaseRenderData, notes map[string]*types.ReleaseNote) []string {
+	merged := make(map[string]bool)
+	for _, version := range data.Versions {
+		note, ok := notes[version.TagName]
+		if !ok {
+			continue
+		}
+		version.MergeReleaseNote(note)
+		merged[version.TagName] = true
+	}
+
+	stale := make([]string, 0)
+	for verStr := range notes {
+		if !merged[verStr] {
+			stale = append(stale, verStr)
+		}
+	}
+	sort.Strings(stale)
+
+	return stale
+}
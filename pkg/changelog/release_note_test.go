@@ -0,0 +1,96 @@
+package changelog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestParseReleaseNote(t *testing.T) {
+	assert := assert.New(t)
+
+	note, err := ParseReleaseNote(`---
+highlights:
+- New scheduler
+knownIssues:
+- VM migration may fail
+upgradeSteps:
+- Backup database
+- Run ocadm upgrade
+---
+
+Thanks to all users.
+`)
+	assert.Nil(err)
+	assert.Equal(&types.ReleaseNote{
+		Highlights:   []string{"New scheduler"},
+		KnownIssues:  []string{"VM migration may fail"},
+		UpgradeSteps: []string{"Backup database", "Run ocadm upgrade"},
+		Content:      "Thanks to all users.",
+	}, note)
+
+	note, err = ParseReleaseNote("Only content\n")
+	assert.Nil(err)
+	assert.Equal(&types.ReleaseNote{Content: "Only content"}, note)
+
+	_, err = ParseReleaseNote("---\nhighlights:\n- foo\n")
+	assert.NotNil(err)
+}
+
+func TestLoadAndMergeReleaseNotes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "release-notes")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	branchDir := filepath.Join(dir, "release", "3.6")
+	assert.Nil(os.MkdirAll(branchDir, 0755))
+	for name, content := range map[string]string{
+		"v3.6.4.md":  "---\nhighlights:\n- Hand-written\nknownIssues:\n- Issue\n---\n",
+		"3.6.1.md":   "Removed version",
+		"README.txt": "not a note",
+	} {
+		assert.Nil(ioutil.WriteFile(filepath.Join(branchDir, name), []byte(content), 0644))
+	}
+
+	notes, err := LoadReleaseNotes(dir, "release/3.6")
+	assert.Nil(err)
+	assert.Len(notes, 2)
+
+	data := &types.ReleaseRenderData{
+		Branch: "release/3.6",
+		Versions: []*types.GlobalVersionRenderData{
+			{
+				TagName:    "3.6.4",
+				Highlights: []*types.Highlight{{Text: "From tag", Repos: []string{"cloudpods"}}},
+			},
+			{
+				TagName: "3.6.3",
+			},
+		},
+	}
+	stale := MergeReleaseNotes(data, notes)
+	assert.Equal([]string{"3.6.1"}, stale)
+	assert.Equal([]*types.Highlight{
+		{Text: "Hand-written"},
+		{Text: "From tag", Repos: []string{"cloudpods"}},
+	}, data.Versions[0].Highlights)
+	assert.Equal([]string{"Issue"}, data.Versions[0].KnownIssues)
+	assert.Len(data.Versions[1].Highlights, 0)
+
+	// no notes of branch
+	notes, err = LoadReleaseNotes(dir, "release/3.5")
+	assert.Nil(err)
+	assert.Len(notes, 0)
+
+	// invalid version
+	assert.Nil(ioutil.WriteFile(filepath.Join(branchDir, "latest.md"), []byte(""), 0644))
+	_, err = LoadReleaseNotes(dir, "release/3.6")
+	assert.NotNil(err)
+}
@@ -0,0 +1,114 @@
+package changelog
+
+import (
+	"strings"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+// NewGlobalStats computes metrics of each release branch, version and repository
+func NewGlobalStats(result *types.GlobalChangeLogResult) (*types.GlobalStats, error) {
+	data, err := NewGlobalRenderData(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "results to render data")
+	}
+
+	stats := &types.GlobalStats{
+		Releases: make([]*types.ReleaseStats, len(data.Releases)),
+	}
+	for idx, rls := range data.Releases {
+		stats.Releases[idx] = newReleaseStats(rls)
+	}
+
+	return stats, nil
+}
+
+func newReleaseStats(rls *types.ReleaseRenderData) *types.ReleaseStats {
+	ret := &types.ReleaseStats{
+		Branch:   rls.Branch,
+		Stats:    types.NewStats(),
+		Versions: make([]*types.VersionStats, len(rls.Versions)),
+	}
+
+	for idx, version := range rls.Versions {
+		vStats := &types.VersionStats{
+			TagName: version.TagName,
+			Date:    version.Date,
+			Stats:   types.NewStats(),
+			Repos:   make([]*types.RepoVersionStats, len(version.Repos)),
+		}
+
+		for rIdx, repo := range version.Repos {
+			rStats := newRepoVersionStats(repo)
+			vStats.Stats.Merge(rStats.Stats)
+			vStats.Repos[rIdx] = rStats
+
+			if vStats.PreviousTagName == "" && rStats.PreviousTagName != "" {
+				vStats.PreviousTagName = strings.TrimPrefix(rStats.PreviousTagName, "v")
+				vStats.DaysSincePrevious = rStats.DaysSincePrevious
+			}
+		}
+
+		// versions are sorted from newest to oldest
+		if idx+1 < len(rls.Versions) {
+			previous := rls.Versions[idx+1]
+			vStats.PreviousTagName = previous.TagName
+			vStats.DaysSincePrevious = types.DaysBetween(previous.Date, version.Date)
+		}
+
+		ret.Stats.Merge(vStats.Stats)
+		ret.Versions[idx] = vStats
+	}
+
+	return ret
+}
+
+func newRepoVersionStats(repo *types.RepoVersionRenderData) *types.RepoVersionStats {
+	ret := &types.RepoVersionStats{
+		Repo:    repo.Repo.Name,
+		TagName: repo.Tag.Name,
+		Date:    repo.Tag.Date,
+		Stats:   types.NewStats(),
+	}
+	if repo.Tag.Previous != nil {
+		ret.PreviousTagName = repo.Tag.Previous.Name
+		ret.DaysSincePrevious = types.DaysBetween(repo.Tag.Previous.Date, repo.Tag.Date)
+	}
+
+	stats := ret.Stats
+	stats.Commits = len(repo.Commits)
+	stats.Reverts = len(repo.RevertCommits)
+	for _, commit := range repo.Commits {
+		typ := commit.Type
+		if typ == "" {
+			typ = "Others"
+		}
+		stats.Types[typ]++
+		scopes := commit.Scopes
+		if len(scopes) == 0 && commit.Scope != "" {
+			scopes = []string{commit.Scope}
+		}
+		for _, scope := range scopes {
+			stats.Scopes[scope]++
+		}
+		if isBreakingChange(commit) {
+			stats.BreakingChanges++
+		}
+	}
+	for _, c := range repo.Contributors {
+		stats.AddContributor(c)
+	}
+
+	return ret
+}
+
+func isBreakingChange(commit *types.Commit) bool {
+	for _, note := range commit.Notes {
+		if strings.Contains(strings.ToUpper(note.Title), "BREAKING") {
+			return true
+		}
+	}
+	return false
+}
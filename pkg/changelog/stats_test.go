@@ -0,0 +1,101 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func newStatsVersion(name string, date time.Time, previous *types.RelateTag, commits []*types.Commit, contributors []*types.Contributor) *types.Version {
+	ver := semver.MustParse(name[1:])
+	version := &types.Version{
+		Tag: &types.Tag{
+			Name:     name,
+			Date:     date,
+			Previous: previous,
+			Version:  &ver,
+		},
+		Commits:      commits,
+		Contributors: contributors,
+	}
+	for _, commit := range commits {
+		if commit.Revert != nil {
+			version.RevertCommits = append(version.RevertCommits, commit)
+		}
+	}
+	return version
+}
+
+func TestNewGlobalStats(t *testing.T) {
+	assert := assert.New(t)
+
+	day := 24 * time.Hour
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	foo := &types.Contributor{CommitContributor: &types.CommitContributor{Name: "foo", Email: "foo@example.com"}, Commits: 2}
+	bar := &types.Contributor{CommitContributor: &types.CommitContributor{Name: "bar", Email: "bar@example.com"}, Commits: 1, FirstTime: true}
+
+	result := &types.GlobalChangeLogResult{
+		Releases: []*types.ReleaseChangeLogResult{
+			{
+				Branch: "release/3.4",
+				Repos: []*types.RepoChangelogResult{
+					{
+						Repo: &types.Repository{Name: "cloudpods"},
+						Versions: []*types.Version{
+							newStatsVersion("v3.4.1", base.Add(10*day), &types.RelateTag{Name: "v3.4.0", Date: base}, []*types.Commit{
+								{Type: "feat", Scope: "host"},
+								{Type: "fix", Scope: "host", Notes: []*types.CommitNote{{Title: "BREAKING CHANGE", Body: "x"}}},
+								{Revert: &types.CommitRevert{Header: "fix: x"}},
+							}, []*types.Contributor{foo, bar}),
+							newStatsVersion("v3.4.0", base, nil, []*types.Commit{
+								{Type: "feat", Scope: "region"},
+							}, []*types.Contributor{foo}),
+						},
+					},
+					{
+						Repo: &types.Repository{Name: "dashboard"},
+						Versions: []*types.Version{
+							newStatsVersion("v3.4.1", base.Add(12*day), &types.RelateTag{Name: "v3.4.0", Date: base.Add(2 * day)}, []*types.Commit{
+								{Type: "fix"},
+							}, []*types.Contributor{bar}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	stats, err := NewGlobalStats(result)
+	assert.Nil(err)
+	assert.Len(stats.Releases, 1)
+
+	rls := stats.Releases[0]
+	assert.Equal(5, rls.Commits)
+	assert.Equal(2, rls.Contributors)
+	assert.Equal(1, rls.FirstTimeContributors)
+	assert.Equal(map[string]int{"feat": 2, "fix": 2, "Others": 1}, rls.Types)
+	assert.Len(rls.Versions, 2)
+
+	v341 := rls.Versions[0]
+	assert.Equal("3.4.1", v341.TagName)
+	assert.Equal("3.4.0", v341.PreviousTagName)
+	assert.Equal(10, v341.DaysSincePrevious)
+	assert.Equal(4, v341.Commits)
+	assert.Equal(1, v341.BreakingChanges)
+	assert.Equal(1, v341.Reverts)
+	assert.Equal(map[string]int{"host": 2}, v341.Scopes)
+	assert.Equal(2, v341.Contributors)
+	assert.Len(v341.Repos, 2)
+	assert.Equal("dashboard", v341.Repos[1].Repo)
+	assert.Equal(10, v341.Repos[1].DaysSincePrevious)
+
+	v340 := rls.Versions[1]
+	assert.Equal("", v340.PreviousTagName)
+	assert.Equal(0, v340.DaysSincePrevious)
+	assert.Equal(1, v340.Contributors)
+	assert.Equal(0, v340.FirstTimeContributors)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/blang/semver/v4"
 
+	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"github.com/yunionio/git-tools/pkg/types"
@@ -58,6 +59,8 @@ func init() {
 		"upper": func(s string) string {
 			return strings.ToUpper(s)
 		},
+		// join the strings with separator
+		"join": strings.Join,
 		// upper case the first character of a string
 		"upperFirst": func(s string) string {
 			if len(s) > 0 {
@@ -82,6 +85,16 @@ func init() {
 		},
 		// commitSummary get the commit summary string
 		"commitSummary": templateCommitSummary,
+		// commitAuthors get the commit author and co-authors
+		"commitAuthors": func(commit *types.Commit) []*types.CommitContributor {
+			return commit.Contributors()
+		},
+		// commitReviewers get the identities from `Reviewed-by` trailers
+		"commitReviewers": func(commit *types.Commit) []*types.CommitContributor {
+			return commit.ReviewedBy
+		},
+		// contributorLink get the markdown link of the contributor
+		"contributorLink": templateContributorLink,
 		// isCommitsEmpty
 		"isCommitsNotEmpty": func(commits []*types.Commit) bool {
 			return len(commits) != 0
@@ -96,19 +109,48 @@ func templateCommitSummary(commit *types.Commit) string {
 	if scope != "" {
 		summary = fmt.Sprintf("**%s:** ", scope)
 	}
-	if commit.Subject != "" {
+	if commit.ReleaseNote != "" {
+		// indent the following lines to keep them in list item
+		summary = fmt.Sprintf("%s%s", summary, strings.ReplaceAll(commit.ReleaseNote, "\n", "\n  "))
+	} else if commit.Subject != "" {
 		summary = fmt.Sprintf("%s%s", summary, commit.Subject)
 	} else {
 		summary = fmt.Sprintf("%s%s", summary, commit.Header)
 	}
 
-	summary = fmt.Sprintf("%s (%s, [%s](mailto:%s))", summary, commit.Hash.Short, commit.Author.Name, commit.Author.Email)
+	credits := make([]string, 0)
+	for _, contributor := range commit.Contributors() {
+		credits = append(credits, templateContributorLink(contributor))
+	}
+	if reviewers := commit.Reviewers(); len(reviewers) > 0 {
+		links := make([]string, 0, len(reviewers))
+		for _, reviewer := range reviewers {
+			links = append(links, templateContributorLink(reviewer))
+		}
+		credits = append(credits, "reviewed by "+strings.Join(links, ", "))
+	}
+
+	summary = fmt.Sprintf("%s (%s, %s)", summary, commit.Hash.Short, strings.Join(credits, ", "))
 	return summary
 }
 
+func templateContributorLink(contributor *types.CommitContributor) string {
+	switch {
+	case contributor.URL != "":
+		return fmt.Sprintf("[%s](%s)", contributor.Name, contributor.URL)
+	case strings.Contains(contributor.Email, "@"):
+		return fmt.Sprintf("[%s](mailto:%s)", contributor.Name, contributor.Email)
+	case contributor.Email != "":
+		// obfuscated email
+		return fmt.Sprintf("%s (%s)", contributor.Name, contributor.Email)
+	}
+	return contributor.Name
+}
+
 func NewGlobalRenderData(result *types.GlobalChangeLogResult) (*types.GlobalRenderData, error) {
 	data := &types.GlobalRenderData{
 		Releases: make([]*types.ReleaseRenderData, len(result.Releases)),
+		Failures: result.Failures,
 	}
 
 	for idx := range result.Releases {
@@ -154,6 +196,7 @@ func NewReleaseRenderData(rls *types.ReleaseChangeLogResult) (*types.ReleaseRend
 		Branch:   rls.Branch,
 		Weight:   rls.Weight,
 		Versions: make([]*types.GlobalVersionRenderData, 0),
+		Failures: rls.Failures,
 	}
 
 	versionMap := make(map[string]*types.GlobalVersionRenderData, 0)
@@ -182,18 +225,105 @@ func NewReleaseRenderData(rls *types.ReleaseChangeLogResult) (*types.ReleaseRend
 		}
 	}
 
+	if rls.NextTag != "" {
+		if err := addDraftVersion(versionMap, rls); err != nil {
+			return nil, errors.Wrapf(err, "add draft version %q", rls.NextTag)
+		}
+	}
+
 	sortVersions, err := getGlobalVersionRenderDatas(versionMap)
 	if err != nil {
 		return nil, errors.Wrap(err, "getGlobalVersionRenderDatas")
 	}
 
 	for _, item := range sortVersions {
+		item.MergeContributors()
+		item.MergeHighlights()
+		item.MergeDiffStat()
+		item.MissingRepos = getMissingRepos(item, rls.Failures)
 		data.Versions = append(data.Versions, item)
 	}
 
 	return data, nil
 }
 
+// getMissingRepos returns the failed repos having the version, or all failed repos if the version is draft.
+// The failed repo with unknown versions is missing in every version
+func getMissingRepos(data *types.GlobalVersionRenderData, failures []*types.RepoFailure) []string {
+	var repos []string
+	for _, failure := range failures {
+		missing := data.Draft || failure.Versions == nil
+		for _, verStr := range failure.Versions {
+			if verStr == data.TagName {
+				missing = true
+			}
+		}
+		if missing {
+			repos = append(repos, failure.Repo)
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// addDraftVersion groups unreleased commits of repos as the version of `NextTag`
+func addDraftVersion(versionMap map[string]*types.GlobalVersionRenderData, rls *types.ReleaseChangeLogResult) error {
+	tagVer, err := semver.Parse(strings.TrimPrefix(rls.NextTag, "v"))
+	if err != nil {
+		return errors.Wrap(err, "parse next tag")
+	}
+	tagVerStr := tagVer.String()
+
+	tagWeight, err := GetSemverStrWeight(tagVerStr)
+	if err != nil {
+		return errors.Wrapf(err, "GetSemverStrWeight %q", tagVerStr)
+	}
+
+	draft := &types.GlobalVersionRenderData{
+		TagName: tagVerStr,
+		Weight:  tagWeight,
+		Draft:   true,
+		Repos:   make([]*types.RepoVersionRenderData, 0),
+	}
+
+	for _, repo := range rls.Repos {
+		if repo.Unreleased == nil || len(repo.Unreleased.Commits) == 0 {
+			continue
+		}
+
+		tag := &types.Tag{
+			Name:    "v" + tagVerStr,
+			Subject: "v" + tagVerStr,
+			Date:    repo.Unreleased.Commits[0].Author.Date,
+			Version: &tagVer,
+		}
+		if len(repo.Versions) > 0 {
+			latest := repo.Versions[0].Tag
+			tag.Previous = &types.RelateTag{
+				Name:    latest.Name,
+				Subject: latest.Subject,
+				Date:    latest.Date,
+			}
+		}
+		if tag.Date.After(draft.Date) {
+			draft.Date = tag.Date
+		}
+
+		draft.Repos = append(draft.Repos, newRepoVersionRenderData(repo.Repo, repo.Unreleased.ToVersion(tag)))
+	}
+
+	if len(draft.Repos) == 0 {
+		log.Warningf("branch %q has no unreleased commits for %q", rls.Branch, rls.NextTag)
+		return nil
+	}
+	if _, ok := versionMap[tagVerStr]; ok {
+		return errors.Errorf("version %q already released", tagVerStr)
+	}
+
+	versionMap[tagVerStr] = draft
+	return nil
+}
+
 func newRepoVersionRenderData(repo *types.Repository, version *types.Version) *types.RepoVersionRenderData {
 	return &types.RepoVersionRenderData{
 		Repo:    repo,
@@ -0,0 +1,225 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestNewReleaseRenderDataDraft(t *testing.T) {
+	assert := assert.New(t)
+
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newCommit := func(date time.Time) *types.Commit {
+		return &types.Commit{
+			Type:   "fix",
+			Author: &types.CommitAuthor{Name: "foo", Date: date},
+		}
+	}
+
+	rls := &types.ReleaseChangeLogResult{
+		Branch:  "release/3.4",
+		NextTag: "v3.4.2",
+		Repos: []*types.RepoChangelogResult{
+			{
+				Repo: &types.Repository{Name: "cloudpods"},
+				Versions: []*types.Version{
+					newStatsVersion("v3.4.1", base, nil, []*types.Commit{newCommit(base)}, nil),
+				},
+				Unreleased: &types.Unreleased{
+					Commits: []*types.Commit{newCommit(base.Add(time.Hour))},
+				},
+			},
+			{
+				Repo: &types.Repository{Name: "dashboard"},
+				Versions: []*types.Version{
+					newStatsVersion("v3.4.1", base, nil, []*types.Commit{newCommit(base)}, nil),
+				},
+				Unreleased: &types.Unreleased{},
+			},
+		},
+	}
+
+	data, err := NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Len(data.Versions, 2)
+
+	draft := data.Versions[0]
+	assert.True(draft.Draft)
+	assert.Equal("3.4.2", draft.TagName)
+	assert.Equal(base.Add(time.Hour), draft.Date)
+	assert.Len(draft.Repos, 1)
+	assert.Equal("cloudpods", draft.Repos[0].Repo.Name)
+	assert.Equal("v3.4.2", draft.Repos[0].Tag.Name)
+	assert.Equal("v3.4.1", draft.Repos[0].Tag.Previous.Name)
+	assert.False(data.Versions[1].Draft)
+
+	rls.NextTag = "v3.4.1"
+	_, err = NewReleaseRenderData(rls)
+	assert.NotNil(err)
+
+	// empty draft is skipped before checking released versions
+	rls.Repos[0].Unreleased = &types.Unreleased{}
+	data, err = NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Len(data.Versions, 1)
+	assert.False(data.Versions[0].Draft)
+}
+
+func TestNewReleaseRenderDataMissingRepos(t *testing.T) {
+	assert := assert.New(t)
+
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	rls := &types.ReleaseChangeLogResult{
+		Branch:  "release/3.4",
+		NextTag: "v3.4.3",
+		Repos: []*types.RepoChangelogResult{
+			{
+				Repo: &types.Repository{Name: "cloudpods"},
+				Versions: []*types.Version{
+					newStatsVersion("v3.4.2", base, nil, []*types.Commit{{Type: "fix"}}, nil),
+					newStatsVersion("v3.4.1", base, nil, []*types.Commit{{Type: "fix"}}, nil),
+				},
+				Unreleased: &types.Unreleased{
+					Commits: []*types.Commit{{Type: "fix", Author: &types.CommitAuthor{Date: base}}},
+				},
+			},
+		},
+		Failures: []*types.RepoFailure{
+			{Branch: "release/3.4", Repo: "ocadm", Error: "parse commits", Versions: []string{"3.4.2"}},
+			{Branch: "release/3.4", Repo: "host", Error: "branch not found tags", Versions: []string{}},
+			{Branch: "release/3.4", Repo: "dashboard", Error: "read tags"},
+		},
+	}
+
+	data, err := NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Equal(rls.Failures, data.Failures)
+	assert.Len(data.Versions, 3)
+	// draft version lacks unreleased commits of all failed repos
+	assert.Equal([]string{"dashboard", "host", "ocadm"}, data.Versions[0].MissingRepos)
+	assert.Equal([]string{"dashboard", "ocadm"}, data.Versions[1].MissingRepos)
+	assert.Equal([]string{"dashboard"}, data.Versions[2].MissingRepos)
+
+	rls.Failures = nil
+	data, err = NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Nil(data.Versions[0].MissingRepos)
+}
+
+func TestNewReleaseRenderDataHighlights(t *testing.T) {
+	assert := assert.New(t)
+
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newVersion := func(body string) *types.Version {
+		version := newStatsVersion("v3.4.1", base, nil, []*types.Commit{{Type: "fix"}}, nil)
+		version.Tag.Body = body
+		return version
+	}
+
+	rls := &types.ReleaseChangeLogResult{
+		Branch: "release/3.4",
+		Repos: []*types.RepoChangelogResult{
+			{
+				Repo:     &types.Repository{Name: "cloudpods"},
+				Versions: []*types.Version{newVersion("- Faster scheduler\n- Support new\n  cloud provider\n\nThanks to all contributors.")},
+			},
+			{
+				Repo:     &types.Repository{Name: "dashboard"},
+				Versions: []*types.Version{newVersion("* faster scheduler\n1. New dashboard")},
+			},
+			{
+				Repo:     &types.Repository{Name: "ocadm"},
+				Versions: []*types.Version{newVersion("")},
+			},
+			{
+				Repo:     &types.Repository{Name: "climc"},
+				Versions: []*types.Version{newVersion("Faster scheduler\n\nNew commands\nof climc")},
+			},
+		},
+	}
+
+	// the prose of cloudpods is ignored as it has list items, the paragraphs of climc are items
+	data, err := NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Len(data.Versions, 1)
+	assert.Equal([]*types.Highlight{
+		{Text: "Faster scheduler", Repos: []string{"climc", "cloudpods", "dashboard"}},
+		{Text: "New commands of climc", Repos: []string{"climc"}},
+		{Text: "Support new cloud provider", Repos: []string{"cloudpods"}},
+		{Text: "New dashboard", Repos: []string{"dashboard"}},
+	}, data.Versions[0].Highlights)
+}
+
+func TestTemplateCommitSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	commit := &types.Commit{
+		Hash:    &types.CommitHash{Short: "65cf1add"},
+		Author:  &types.CommitAuthor{Name: "Foo", Email: "foo@example.com"},
+		Scope:   "core",
+		Subject: "Add new feature (#12)",
+	}
+	assert.Equal("**core:** Add new feature (#12) (65cf1add, [Foo](mailto:foo@example.com))", templateCommitSummary(commit))
+
+	commit.ReleaseNote = "Support new feature.\nIt is enabled by default."
+	assert.Equal("**core:** Support new feature.\n  It is enabled by default. (65cf1add, [Foo](mailto:foo@example.com))", templateCommitSummary(commit))
+
+	commit.ReleaseNote = ""
+	commit.ReviewedBy = []*types.CommitContributor{
+		{Name: "Foo", Email: "foo@example.com"},
+		{Name: "Bar", Email: "bar@example.com"},
+	}
+	assert.Equal("**core:** Add new feature (#12) (65cf1add, [Foo](mailto:foo@example.com), reviewed by [Bar](mailto:bar@example.com))", templateCommitSummary(commit))
+}
+
+func TestNewReleaseRenderDataDiffStat(t *testing.T) {
+	assert := assert.New(t)
+
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newVersion := func(commits []*types.Commit) *types.Version {
+		ver := newStatsVersion("v3.4.1", base, nil, commits, nil)
+		ver.DiffStat = types.NewDiffStat(commits)
+		return ver
+	}
+
+	rls := &types.ReleaseChangeLogResult{
+		Branch: "release/3.4",
+		Repos: []*types.RepoChangelogResult{
+			{
+				Repo: &types.Repository{Name: "cloudpods"},
+				Versions: []*types.Version{
+					newVersion([]*types.Commit{
+						{Type: "fix", Files: []string{"a.go", "b.go"}, Insertions: 10, Deletions: 2},
+						{Type: "feat", Files: []string{"a.go"}, Insertions: 5, Deletions: 1},
+					}),
+				},
+			},
+			{
+				Repo: &types.Repository{Name: "ocadm"},
+				Versions: []*types.Version{
+					newVersion([]*types.Commit{
+						{Type: "fix", Files: []string{"a.go"}, Insertions: 1},
+					}),
+				},
+			},
+		},
+	}
+
+	data, err := NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Len(data.Versions, 1)
+	assert.Equal(&types.DiffStat{Files: 2, Insertions: 15, Deletions: 3}, data.Versions[0].Repos[0].DiffStat)
+	assert.Equal(&types.DiffStat{Files: 3, Insertions: 16, Deletions: 3}, data.Versions[0].DiffStat)
+	assert.Equal("+16/-3 across 3 files", data.Versions[0].DiffStat.String())
+
+	for _, repo := range rls.Repos {
+		repo.Versions[0].DiffStat = nil
+	}
+	data, err = NewReleaseRenderData(rls)
+	assert.Nil(err)
+	assert.Nil(data.Versions[0].DiffStat)
+}
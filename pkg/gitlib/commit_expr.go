@@ -0,0 +1,537 @@
+package gitlib
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+const (
+	ErrInvalidCommitExpr = errors.Error("invalid commit expression")
+)
+
+var (
+	exprOperators = []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!", "(", ")", ","}
+	// comparison operators, `in` is a word
+	exprCompareOperators = map[string]bool{
+		"==": true, "!=": true, "=~": true, "!~": true,
+		"<": true, "<=": true, ">": true, ">=": true,
+		"in": true,
+	}
+	exprDateLayouts = []string{"2006-01-02", time.RFC3339}
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// CommitExpr is the compiled filter expression of commit, e.g.
+//
+//	type in (feat,fix) && !(scope =~ "^ci") && author.email !~ "bot@"
+//
+// The properties are fields of `Commit` matched in a case insensitive way, operators are
+//
+//	== != in        equality of string, int, bool and date
+//	=~ !~           regexp matching of string
+//	< <= > >=       comparison of string, int and date (`2006-01-02` or RFC3339)
+//	! && || ( )     logical operators
+//
+// A bare property is true if it's not empty, e.g. `!revert`.
+// The string slice property (e.g. `scopes`) matches if any element matches
+type CommitExpr struct {
+	src  string
+	root exprNode
+}
+
+// CompileCommitExpr parses the filter expression
+func CompileCommitExpr(src string) (*CommitExpr, error) {
+	tokens, err := lexCommitExpr(src)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCommitExpr, "%q: %v", src, err)
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != exprTokenEOF {
+		err = errors.Errorf("unexpected %q at %d", p.peek().text, p.peek().pos)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCommitExpr, "%q: %v", src, err)
+	}
+
+	return &CommitExpr{
+		src:  src,
+		root: root,
+	}, nil
+}
+
+func (e *CommitExpr) String() string {
+	return e.src
+}
+
+// Match evaluates the expression on commit, string equality is case insensitive if `noCaseSensitive`
+func (e *CommitExpr) Match(commit *types.Commit, noCaseSensitive bool) bool {
+	return e.root.eval(reflect.ValueOf(commit), noCaseSensitive)
+}
+
+type exprNode interface {
+	eval(commit reflect.Value, noCaseSensitive bool) bool
+}
+
+type exprNot struct {
+	x exprNode
+}
+
+func (n *exprNot) eval(commit reflect.Value, noCaseSensitive bool) bool {
+	return !n.x.eval(commit, noCaseSensitive)
+}
+
+type exprAnd struct {
+	x, y exprNode
+}
+
+func (n *exprAnd) eval(commit reflect.Value, noCaseSensitive bool) bool {
+	return n.x.eval(commit, noCaseSensitive) && n.y.eval(commit, noCaseSensitive)
+}
+
+type exprOr struct {
+	x, y exprNode
+}
+
+func (n *exprOr) eval(commit reflect.Value, noCaseSensitive bool) bool {
+	return n.x.eval(commit, noCaseSensitive) || n.y.eval(commit, noCaseSensitive)
+}
+
+// exprProp is the field path of `Commit`
+type exprProp struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+func newExprProp(name string) (*exprProp, error) {
+	prop := &exprProp{
+		name: name,
+		typ:  reflect.TypeOf(types.Commit{}),
+	}
+
+	for _, key := range strings.Split(name, ".") {
+		if prop.typ.Kind() == reflect.Ptr {
+			prop.typ = prop.typ.Elem()
+		}
+		if prop.typ.Kind() != reflect.Struct || prop.typ == timeType {
+			return nil, errors.Errorf("unknown property %q", name)
+		}
+		field, ok := prop.typ.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, key)
+		})
+		if !ok || len(field.Index) != 1 {
+			return nil, errors.Errorf("unknown property %q", name)
+		}
+		prop.index = append(prop.index, field.Index[0])
+		prop.typ = field.Type
+	}
+
+	return prop, nil
+}
+
+// get returns the property value, false is returned if any pointer in path is nil
+func (p *exprProp) get(commit reflect.Value) (reflect.Value, bool) {
+	v := commit
+	for _, i := range p.index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+type exprTruthy struct {
+	prop *exprProp
+}
+
+func (n *exprTruthy) eval(commit reflect.Value, noCaseSensitive bool) bool {
+	v, ok := n.prop.get(commit)
+	if !ok {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	case reflect.Bool:
+		return v.Bool()
+	}
+	return !v.IsZero()
+}
+
+// exprCompare is the comparison of property with positive operator (`==`, `=~`, `<`, `<=`, `>`, `>=`),
+// `!=` and `!~` are negated by exprNot, `in` is `==` with multiple values
+type exprCompare struct {
+	prop   *exprProp
+	op     string
+	values []interface{}
+}
+
+func newExprCompare(prop *exprProp, op string, texts []string) (exprNode, error) {
+	negative := false
+	switch op {
+	case "!=":
+		op, negative = "==", true
+	case "!~":
+		op, negative = "=~", true
+	case "in":
+		op = "=="
+	}
+
+	typ := prop.typ
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String {
+		typ = typ.Elem()
+	}
+
+	n := &exprCompare{
+		prop:   prop,
+		op:     op,
+		values: make([]interface{}, len(texts)),
+	}
+	for i, text := range texts {
+		var (
+			val interface{}
+			err error
+		)
+		switch {
+		case typ.Kind() == reflect.String && op == "=~":
+			val, err = regexp.Compile(text)
+		case typ.Kind() == reflect.String:
+			val = text
+		case op == "=~":
+			err = errors.Errorf("property %q of %s does not support regexp", prop.name, typ)
+		case typ == timeType:
+			val, err = parseExprDate(text)
+		case typ.Kind() == reflect.Int:
+			val, err = strconv.Atoi(text)
+		case typ.Kind() == reflect.Bool && op == "==":
+			val, err = strconv.ParseBool(text)
+		default:
+			err = errors.Errorf("property %q of %s can't be compared by %q", prop.name, typ, op)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.values[i] = val
+	}
+
+	if negative {
+		return &exprNot{x: n}, nil
+	}
+	return n, nil
+}
+
+func parseExprDate(text string) (time.Time, error) {
+	for _, layout := range exprDateLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid date %q, layouts are %s", text, strings.Join(exprDateLayouts, ", "))
+}
+
+func (n *exprCompare) eval(commit reflect.Value, noCaseSensitive bool) bool {
+	v, ok := n.prop.get(commit)
+	if !ok {
+		return false
+	}
+
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if n.match(v.Index(i), noCaseSensitive) {
+				return true
+			}
+		}
+		return false
+	}
+	return n.match(v, noCaseSensitive)
+}
+
+func (n *exprCompare) match(val reflect.Value, noCaseSensitive bool) bool {
+	for _, expected := range n.values {
+		var cmp int
+		switch e := expected.(type) {
+		case *regexp.Regexp:
+			if e.MatchString(val.String()) {
+				return true
+			}
+			continue
+		case string:
+			s := val.String()
+			if noCaseSensitive {
+				s, e = strings.ToLower(s), strings.ToLower(e)
+			}
+			cmp = strings.Compare(s, e)
+		case time.Time:
+			t := val.Interface().(time.Time)
+			switch {
+			case t.Before(e):
+				cmp = -1
+			case t.After(e):
+				cmp = 1
+			}
+		case int:
+			i := int(val.Int())
+			switch {
+			case i < e:
+				cmp = -1
+			case i > e:
+				cmp = 1
+			}
+		case bool:
+			if val.Bool() != e {
+				cmp = 1
+			}
+		}
+
+		if compareResult(cmp, n.op) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareResult(cmp int, op string) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+const (
+	exprTokenEOF = iota
+	exprTokenWord
+	exprTokenString
+	exprTokenOperator
+)
+
+type exprToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+func isExprWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()!,&|=<>~"'`, r)
+}
+
+func lexCommitExpr(src string) ([]exprToken, error) {
+	tokens := []exprToken{}
+
+	for pos := 0; pos < len(src); {
+		r := rune(src[pos])
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '"':
+			end := pos + 1
+			for ; end < len(src) && src[end] != '"'; end++ {
+				if src[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(src) {
+				return nil, errors.Errorf("unterminated string at %d", pos)
+			}
+			text, err := strconv.Unquote(src[pos : end+1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "string at %d", pos)
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenString, text: text, pos: pos})
+			pos = end + 1
+		case r == '\'':
+			end := strings.IndexByte(src[pos+1:], '\'')
+			if end < 0 {
+				return nil, errors.Errorf("unterminated string at %d", pos)
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenString, text: src[pos+1 : pos+1+end], pos: pos})
+			pos += end + 2
+		case isExprWordRune(r):
+			end := pos
+			for end < len(src) && isExprWordRune(rune(src[end])) {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenWord, text: src[pos:end], pos: pos})
+			pos = end
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(src[pos:], op) {
+					tokens = append(tokens, exprToken{kind: exprTokenOperator, text: op, pos: pos})
+					pos += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errors.Errorf("unexpected %q at %d", r, pos)
+			}
+		}
+	}
+
+	return append(tokens, exprToken{kind: exprTokenEOF, text: "EOF", pos: len(src)}), nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != exprTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) isOperator(op string) bool {
+	tok := p.peek()
+	return tok.kind == exprTokenOperator && tok.text == op
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.isOperator(op) {
+		return errors.Errorf("expect %q but got %q at %d", op, p.peek().text, p.peek().pos)
+	}
+	p.next()
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprOr{x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprAnd{x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	switch {
+	case p.isOperator("!"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{x: x}, nil
+	case p.isOperator("("):
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	tok := p.next()
+	if tok.kind != exprTokenWord {
+		return nil, errors.Errorf("expect property but got %q at %d", tok.text, tok.pos)
+	}
+	prop, err := newExprProp(tok.text)
+	if err != nil {
+		return nil, err
+	}
+
+	opTok := p.peek()
+	if (opTok.kind != exprTokenOperator && opTok.kind != exprTokenWord) || !exprCompareOperators[opTok.text] {
+		return &exprTruthy{prop: prop}, nil
+	}
+	p.next()
+
+	var texts []string
+	if opTok.text == "in" {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			text, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			texts = append(texts, text)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	} else {
+		text, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		texts = []string{text}
+	}
+
+	return newExprCompare(prop, opTok.text, texts)
+}
+
+func (p *exprParser) parseValue() (string, error) {
+	tok := p.next()
+	if tok.kind != exprTokenWord && tok.kind != exprTokenString {
+		return "", errors.Errorf("expect value but got %q at %d", tok.text, tok.pos)
+	}
+	return tok.text, nil
+}
@@ -0,0 +1,91 @@
+package gitlib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestCommitExpr(t *testing.T) {
+	assert := assert.New(t)
+
+	commit := &types.Commit{
+		Hash: &types.CommitHash{Long: "65cf1add9735dcc4810dda3312b0792236c97c4e", Short: "65cf1add"},
+		Author: &types.CommitAuthor{
+			Name:  "dependabot",
+			Email: "ci-bot@example.com",
+			Date:  time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC),
+		},
+		Type:     "fix",
+		Scope:    "ci,host",
+		Scopes:   []string{"ci", "host"},
+		Subject:  "Bump version",
+		Body:     "Internal only\nSigned-off-by: foo",
+		ChangeID: "I8473b95934b5732ac55d26311a706c9c2bde9940",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`type == fix`, true},
+		{`type == "Fix"`, false},
+		{`type != fix`, false},
+		{`type in (feat,fix)`, true},
+		{`type in (feat, docs)`, false},
+		{`!(type in (feat,docs))`, true},
+		{`scope =~ "^ci"`, true},
+		{`!(scope =~ "^ci")`, false},
+		{`scopes == host`, true},
+		{`scopes != host`, false},
+		{`scopes =~ '^comp'`, false},
+		{`author.email !~ "bot@"`, false},
+		{`author.email =~ "^ci\\-bot@"`, true},
+		{`Author.Name == dependabot && subject =~ "(?i)bump"`, true},
+		{`author.date >= 2021-03-05`, true},
+		{`author.date < 2021-03-05`, false},
+		{`author.date < 2021-03-05T21:00:00+08:00 || type == feat`, true},
+		{`committer.date > 2021-01-01`, false},
+		{`committer.date <= 2021-01-01`, false},
+		{`body =~ "(?m)^Internal"`, true},
+		{`changeId == I8473b95934b5732ac55d26311a706c9c2bde9940`, true},
+		{`merge`, false},
+		{`!revert && author`, true},
+		{`type == feat || type == fix && scope == ci`, false},
+		{`(type == feat || type == fix) && scopes == ci`, true},
+	}
+	for _, tt := range tests {
+		expr, err := CompileCommitExpr(tt.expr)
+		if !assert.Nil(err, tt.expr) {
+			continue
+		}
+		assert.Equal(tt.want, expr.Match(commit, false), tt.expr)
+	}
+
+	expr, err := CompileCommitExpr(`type == "Fix"`)
+	assert.Nil(err)
+	assert.True(expr.Match(commit, true))
+
+	for _, src := range []string{
+		``,
+		`type ==`,
+		`type in (feat`,
+		`type == feat &&`,
+		`(type == feat`,
+		`type feat`,
+		`unknown == foo`,
+		`author.unknown == foo`,
+		`author.date == yesterday`,
+		`author.date =~ "^2021"`,
+		`scope =~ "("`,
+		`notes == foo`,
+		`subject == "unterminated`,
+		`type = feat`,
+	} {
+		_, err := CompileCommitExpr(src)
+		assert.NotNil(err, src)
+	}
+}
@@ -4,21 +4,72 @@ import (
 	"sort"
 	"strings"
 
+	"yunion.io/x/log"
+
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
 type CommitExtractor interface {
+	// Filter normalizes commits and drops the ones not matched by the expression filters
+	Filter(commits []*types.Commit) []*types.Commit
 	Extract(commits []*types.Commit) ([]*types.CommitGroup, []*types.Commit, []*types.Commit, []*types.CommitNoteGroup)
 }
 
 type commitExtractor struct {
 	opts *types.ChangelogConfigOptions
+	// lower case alias or canonical name to canonical name
+	typeAliases  map[string]string
+	scopeAliases map[string]string
+	exprFilters  []*CommitExprFilter
 }
 
-func NewCommitExtractor(opts *types.ChangelogConfigOptions) *commitExtractor {
+// NewCommitExtractor creates the extractor, the filters are applied by `Filter` besides `opts.Filter`.
+// The invalid filters are ignored with error logs
+func NewCommitExtractor(opts *types.ChangelogConfigOptions, filters ...*types.CommitFilterConfig) *commitExtractor {
+	typeAliases := newAliasMap(opts.TypeAliases)
+	for typ := range opts.CommitGroupTitleMaps {
+		if _, ok := typeAliases[strings.ToLower(typ)]; !ok {
+			typeAliases[strings.ToLower(typ)] = typ
+		}
+	}
+
+	if opts.Filter != nil {
+		filters = append([]*types.CommitFilterConfig{opts.Filter}, filters...)
+	}
+	exprFilters := make([]*CommitExprFilter, 0, len(filters))
+	for _, conf := range filters {
+		f, err := NewCommitExprFilter(conf)
+		if err != nil {
+			log.Errorf("ignore commit filter: %v", err)
+			continue
+		}
+		exprFilters = append(exprFilters, f)
+	}
+
 	return &commitExtractor{
-		opts: opts,
+		opts:         opts,
+		typeAliases:  typeAliases,
+		scopeAliases: newAliasMap(opts.ScopeAliases),
+		exprFilters:  exprFilters,
+	}
+}
+
+func newAliasMap(aliases map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for _, canonical := range aliases {
+		ret[strings.ToLower(canonical)] = canonical
+	}
+	for alias, canonical := range aliases {
+		ret[strings.ToLower(alias)] = canonical
 	}
+	return ret
+}
+
+func (e *commitExtractor) Filter(commits []*types.Commit) []*types.Commit {
+	for _, commit := range commits {
+		e.normalizeCommit(commit)
+	}
+	return commitExprFilter(commits, e.exprFilters, e.opts.NoCaseSensitive)
 }
 
 func (e *commitExtractor) Extract(commits []*types.Commit) ([]*types.CommitGroup, []*types.Commit, []*types.Commit, []*types.CommitNoteGroup) {
@@ -27,6 +78,10 @@ func (e *commitExtractor) Extract(commits []*types.Commit) ([]*types.CommitGroup
 	mergeCommits := []*types.Commit{}
 	revertCommits := []*types.Commit{}
 
+	for _, commit := range commits {
+		e.normalizeCommit(commit)
+	}
+
 	filteredCommits := commitFilter(commits, e.opts.CommitFilters, e.opts.NoCaseSensitive)
 
 	othersGroup := &types.CommitGroup{
@@ -62,18 +117,105 @@ func (e *commitExtractor) Extract(commits []*types.Commit) ([]*types.CommitGroup
 		commitGroups = append(commitGroups, othersGroup)
 	}
 
-	e.sortCommitGroups(commitGroups)
+	e.sortCommitGroups(commitGroups, e.opts.CommitGroupSortBy)
+	e.processCommitSubGroups(commitGroups)
 	e.sortNoteGroups(noteGroups)
 
 	return commitGroups, mergeCommits, revertCommits, noteGroups
 }
 
-func (e *commitExtractor) processCommitGroups(groups *[]*types.CommitGroup, commit *types.Commit, noCaseSensitive bool) bool {
-	var group *types.CommitGroup
+// normalizeCommit maps type and scopes to the canonical ones, the comma separated scopes are split
+func (e *commitExtractor) normalizeCommit(commit *types.Commit) {
+	if canonical, ok := e.typeAliases[strings.ToLower(commit.Type)]; ok {
+		commit.Type = canonical
+	}
+
+	if commit.Scope == "" {
+		return
+	}
+	scopes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, scope := range strings.Split(commit.Scope, ",") {
+		scope = strings.TrimSpace(scope)
+		if canonical, ok := e.scopeAliases[strings.ToLower(scope)]; ok {
+			scope = canonical
+		}
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	commit.Scopes = scopes
+	commit.Scope = strings.Join(scopes, ",")
+}
 
+func (e *commitExtractor) processCommitGroups(groups *[]*types.CommitGroup, commit *types.Commit, noCaseSensitive bool) bool {
 	// commit group
 	raw, ttl := e.commitGroupTitle(commit)
 
+	return appendCommitToGroups(groups, commit, raw, ttl, noCaseSensitive)
+}
+
+// processCommitSubGroups groups commits of each group by `CommitSubGroupBy` option,
+// the commits without the property are put into the last `Others` sub group
+func (e *commitExtractor) processCommitSubGroups(groups []*types.CommitGroup) {
+	if e.opts.CommitSubGroupBy == "" {
+		return
+	}
+
+	sortBy := e.opts.CommitSubGroupSortBy
+	if sortBy == "" {
+		sortBy = "Title"
+	}
+
+	for _, group := range groups {
+		group.SubGroups = []*types.CommitGroup{}
+		othersGroup := &types.CommitGroup{
+			RawTitle: "Others",
+			Title:    "Others",
+			Commits:  make([]*types.Commit, 0),
+		}
+
+		for _, commit := range group.Commits {
+			appended := false
+			for _, raw := range commitSubGroupTitles(commit, e.opts.CommitSubGroupBy) {
+				if appendCommitToGroups(&group.SubGroups, commit, raw, raw, e.opts.NoCaseSensitive) {
+					appended = true
+				}
+			}
+			if !appended {
+				othersGroup.Commits = append(othersGroup.Commits, commit)
+			}
+		}
+
+		e.sortCommitGroups(group.SubGroups, sortBy)
+		if len(othersGroup.Commits) != 0 {
+			e.sortCommitGroups([]*types.CommitGroup{othersGroup}, sortBy)
+			group.SubGroups = append(group.SubGroups, othersGroup)
+		}
+	}
+}
+
+// commitSubGroupTitles returns the values of property, the commit of `[]string` property (e.g. `Scopes`, `Components`)
+// is put into the sub group of each value
+func commitSubGroupTitles(commit *types.Commit, prop string) []string {
+	v, ok := dotGet(commit, prop)
+	if !ok {
+		return nil
+	}
+	switch raw := v.(type) {
+	case string:
+		return []string{raw}
+	case []string:
+		return raw
+	}
+	return nil
+}
+
+func appendCommitToGroups(groups *[]*types.CommitGroup, commit *types.Commit, raw string, ttl string, noCaseSensitive bool) bool {
+	var group *types.CommitGroup
+
 	for _, g := range *groups {
 		rawTitleTmp := g.RawTitle
 		if noCaseSensitive {
@@ -150,7 +292,7 @@ func (e *commitExtractor) commitGroupTitle(commit *types.Commit) (string, string
 	return raw, ttl
 }
 
-func (e *commitExtractor) sortCommitGroups(groups []*types.CommitGroup) {
+func (e *commitExtractor) sortCommitGroups(groups []*types.CommitGroup, sortBy string) {
 	// groups
 	sort.Slice(groups, func(i, j int) bool {
 		var (
@@ -158,12 +300,12 @@ func (e *commitExtractor) sortCommitGroups(groups []*types.CommitGroup) {
 			ok   bool
 		)
 
-		a, ok = dotGet(groups[i], e.opts.CommitGroupSortBy)
+		a, ok = dotGet(groups[i], sortBy)
 		if !ok {
 			return false
 		}
 
-		b, ok = dotGet(groups[j], e.opts.CommitGroupSortBy)
+		b, ok = dotGet(groups[j], sortBy)
 		if !ok {
 			return false
 		}
@@ -137,3 +137,155 @@ func TestCommitExtractor(t *testing.T) {
 		},
 	}, noteGroups)
 }
+
+func TestCommitExtractorAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	extractor := NewCommitExtractor(&types.ChangelogConfigOptions{
+		CommitSortBy:      "Scope",
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "Title",
+		CommitGroupTitleMaps: map[string]string{
+			"feat": "Features",
+			"fix":  "Bug Fixes",
+		},
+		TypeAliases: map[string]string{
+			"feature": "feat",
+			"bugfix":  "fix",
+		},
+		ScopeAliases: map[string]string{
+			"hostman": "host",
+		},
+		CommitFilters: map[string][]string{
+			"Type": {"feat", "fix"},
+		},
+	})
+
+	commits := []*types.Commit{
+		{Type: "feat", Scope: "region", Header: "1"},
+		{Type: "feature", Scope: "Host", Header: "2"},
+		{Type: "Feat", Scope: "hostman", Header: "3"},
+		{Type: "BugFix", Scope: "region, host,hostman", Header: "4"},
+		{Type: "docs", Scope: "host", Header: "5"},
+	}
+
+	commitGroups, _, _, _ := extractor.Extract(commits)
+	assert.Len(commitGroups, 2)
+
+	assert.Equal("Bug Fixes", commitGroups[0].Title)
+	assert.Equal([]*types.Commit{commits[3]}, commitGroups[0].Commits)
+	assert.Equal("fix", commits[3].Type)
+	assert.Equal("region,host", commits[3].Scope)
+	assert.Equal([]string{"region", "host"}, commits[3].Scopes)
+
+	assert.Equal("Features", commitGroups[1].Title)
+	assert.Len(commitGroups[1].Commits, 3)
+	for _, commit := range commitGroups[1].Commits {
+		assert.Equal("feat", commit.Type)
+	}
+	assert.Equal("host", commits[1].Scope)
+	assert.Equal("host", commits[2].Scope)
+	assert.Equal("region", commitGroups[1].Commits[2].Scope)
+}
+
+func TestCommitExtractorSubGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	extractor := NewCommitExtractor(&types.ChangelogConfigOptions{
+		CommitSortBy:      "Header",
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "Title",
+		CommitSubGroupBy:  "Scope",
+		CommitGroupTitleMaps: map[string]string{
+			"fix": "Bug Fixes",
+		},
+	})
+
+	commits := []*types.Commit{
+		{Type: "fix", Scope: "host", Header: "1"},
+		{Type: "fix", Scope: "", Header: "2"},
+		{Type: "fix", Scope: "compute", Header: "3"},
+		{Type: "fix", Scope: "host", Header: "4"},
+		{Type: "feat", Scope: "compute", Header: "5"},
+	}
+
+	commitGroups, _, _, _ := extractor.Extract(commits)
+	assert.Len(commitGroups, 2)
+
+	fixGroup := commitGroups[0]
+	assert.Equal("Bug Fixes", fixGroup.Title)
+	assert.Len(fixGroup.Commits, 4)
+	assert.Equal([]*types.CommitGroup{
+		{
+			RawTitle: "compute",
+			Title:    "compute",
+			Commits:  []*types.Commit{commits[2]},
+		},
+		{
+			RawTitle: "host",
+			Title:    "host",
+			Commits:  []*types.Commit{commits[0], commits[3]},
+		},
+		{
+			RawTitle: "Others",
+			Title:    "Others",
+			Commits:  []*types.Commit{commits[1]},
+		},
+	}, fixGroup.SubGroups)
+
+	featGroup := commitGroups[1]
+	assert.Equal("Feat", featGroup.Title)
+	assert.Len(featGroup.SubGroups, 1)
+	assert.Equal("compute", featGroup.SubGroups[0].Title)
+}
+
+func TestCommitExtractorSubGroupsOfList(t *testing.T) {
+	assert := assert.New(t)
+
+	extractor := NewCommitExtractor(&types.ChangelogConfigOptions{
+		CommitSortBy:      "Header",
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "Title",
+		CommitSubGroupBy:  "Components",
+	})
+
+	commits := []*types.Commit{
+		{Type: "fix", Components: []string{"compute", "host"}, Header: "1"},
+		{Type: "fix", Components: []string{"host"}, Header: "2"},
+		{Type: "fix", Header: "3"},
+	}
+
+	commitGroups, _, _, _ := extractor.Extract(commits)
+	assert.Len(commitGroups, 1)
+	assert.Equal([]*types.CommitGroup{
+		{
+			RawTitle: "compute",
+			Title:    "compute",
+			Commits:  []*types.Commit{commits[0]},
+		},
+		{
+			RawTitle: "host",
+			Title:    "host",
+			Commits:  []*types.Commit{commits[0], commits[1]},
+		},
+		{
+			RawTitle: "Others",
+			Title:    "Others",
+			Commits:  []*types.Commit{commits[2]},
+		},
+	}, commitGroups[0].SubGroups)
+
+	// multiple scopes of `Scopes`
+	extractor = NewCommitExtractor(&types.ChangelogConfigOptions{
+		CommitSortBy:      "Header",
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "Title",
+		CommitSubGroupBy:  "Scopes",
+	})
+	commitGroups, _, _, _ = extractor.Extract([]*types.Commit{
+		{Type: "feat", Scope: "host, region", Header: "4"},
+	})
+	assert.Len(commitGroups[0].SubGroups, 2)
+	assert.Equal("host", commitGroups[0].SubGroups[0].Title)
+	assert.Equal("region", commitGroups[0].SubGroups[1].Title)
+}
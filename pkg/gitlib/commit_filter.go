@@ -3,6 +3,8 @@ package gitlib
 import (
 	"strings"
 
+	"yunion.io/x/pkg/errors"
+
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
@@ -60,3 +62,69 @@ func commitFilter(commits []*types.Commit, filters map[string][]string, noCaseSe
 
 	return res
 }
+
+// CommitExprFilter keeps commits matched by any of include expressions and none of exclude expressions
+type CommitExprFilter struct {
+	includes []*CommitExpr
+	excludes []*CommitExpr
+}
+
+func NewCommitExprFilter(conf *types.CommitFilterConfig) (*CommitExprFilter, error) {
+	f := &CommitExprFilter{}
+	for _, src := range conf.Include {
+		expr, err := CompileCommitExpr(src)
+		if err != nil {
+			return nil, errors.Wrap(err, "include")
+		}
+		f.includes = append(f.includes, expr)
+	}
+	for _, src := range conf.Exclude {
+		expr, err := CompileCommitExpr(src)
+		if err != nil {
+			return nil, errors.Wrap(err, "exclude")
+		}
+		f.excludes = append(f.excludes, expr)
+	}
+	return f, nil
+}
+
+func (f *CommitExprFilter) Match(commit *types.Commit, noCaseSensitive bool) bool {
+	include := len(f.includes) == 0
+	for _, expr := range f.includes {
+		if expr.Match(commit, noCaseSensitive) {
+			include = true
+			break
+		}
+	}
+	if !include {
+		return false
+	}
+
+	for _, expr := range f.excludes {
+		if expr.Match(commit, noCaseSensitive) {
+			return false
+		}
+	}
+	return true
+}
+
+func commitExprFilter(commits []*types.Commit, filters []*CommitExprFilter, noCaseSensitive bool) []*types.Commit {
+	if len(filters) == 0 {
+		return commits
+	}
+
+	res := []*types.Commit{}
+	for _, commit := range commits {
+		include := true
+		for _, f := range filters {
+			if !f.Match(commit, noCaseSensitive) {
+				include = false
+				break
+			}
+		}
+		if include {
+			res = append(res, commit)
+		}
+	}
+	return res
+}
@@ -145,3 +145,54 @@ func TestCommitFilter(t *testing.T) {
 		}, false)),
 	)
 }
+
+func TestCommitExprFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	commits := []*types.Commit{
+		{Type: "feat", Scope: "host", Subject: "1", Author: &types.CommitAuthor{Email: "foo@example.com"}},
+		{Type: "fix", Scope: "ci", Subject: "2", Author: &types.CommitAuthor{Email: "foo@example.com"}},
+		{Type: "fix", Scope: "region", Subject: "3", Author: &types.CommitAuthor{Email: "ci-bot@example.com"}},
+		{Type: "docs", Scope: "host", Subject: "4", Author: &types.CommitAuthor{Email: "foo@example.com"}},
+		{Type: "chore", Scope: "host", Subject: "5", Author: &types.CommitAuthor{Email: "foo@example.com"}},
+	}
+
+	pickCommitSubjects := func(groups []*types.CommitGroup) []string {
+		res := []string{}
+		for _, group := range groups {
+			for _, commit := range group.Commits {
+				res = append(res, commit.Subject)
+			}
+		}
+		return res
+	}
+
+	opts := &types.ChangelogConfigOptions{
+		CommitSortBy:      "Subject",
+		CommitGroupBy:     "Type",
+		CommitGroupSortBy: "RawTitle",
+		Filter: &types.CommitFilterConfig{
+			Include: []string{`type in (feat,fix)`, `type == docs`},
+			Exclude: []string{`scope =~ "^ci"`},
+		},
+	}
+
+	extract := func(extractor *commitExtractor) []*types.CommitGroup {
+		groups, _, _, _ := extractor.Extract(extractor.Filter(commits))
+		return groups
+	}
+
+	assert.Equal([]string{"4", "1", "3"}, pickCommitSubjects(extract(NewCommitExtractor(opts))))
+
+	assert.Equal([]string{"4", "1"}, pickCommitSubjects(extract(NewCommitExtractor(opts, &types.CommitFilterConfig{
+		Exclude: []string{`author.email =~ "bot@"`},
+	}))))
+
+	// invalid filter is ignored
+	assert.Equal([]string{"4", "1", "3"}, pickCommitSubjects(extract(NewCommitExtractor(opts, &types.CommitFilterConfig{
+		Exclude: []string{`author.email ~= "bot@"`},
+	}))))
+
+	_, err := NewCommitExprFilter(&types.CommitFilterConfig{Include: []string{`type in feat`}})
+	assert.NotNil(err)
+}
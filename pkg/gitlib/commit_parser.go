@@ -2,6 +2,8 @@ package gitlib
 
 // code references from https://github.com/git-chglog/git-chglog
 import (
+	"path"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -9,6 +11,8 @@ import (
 
 	gitcmd "github.com/tsuyoshiwada/go-gitcmd"
 
+	"yunion.io/x/log"
+
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
@@ -23,6 +27,8 @@ var (
 	committerField = "COMMITTER"
 	subjectField   = "SUBJECT"
 	bodyField      = "BODY"
+	notesField     = "NOTES"
+	filesField     = "FILES"
 
 	// formats
 	hashFormat      = hashField + ":%H\t%h"
@@ -30,6 +36,9 @@ var (
 	committerFormat = committerField + ":%cn\t%ce\t%ct"
 	subjectFormat   = subjectField + ":%s"
 	bodyFormat      = bodyField + ":%b"
+	notesFormat     = notesField + ":%N"
+	// the files of `--name-only` or `--numstat` are printed after format
+	filesFormat = filesField + ":"
 
 	// log
 	logFormat = separator + strings.Join([]string{
@@ -39,6 +48,17 @@ var (
 		subjectFormat,
 		bodyFormat,
 	}, delimiter)
+
+	// trailers
+	coAuthoredByTrailer = "co-authored-by"
+	signedOffByTrailer  = "signed-off-by"
+	reviewedByTrailer   = "reviewed-by"
+
+	// release note
+	releaseNoteLang = "release-note"
+	releaseNoteNone = "NONE"
+
+	reChangeID = regexp.MustCompile(`^\s*Change-Id:\s*(I[0-9a-fA-F]{40})\s*$`)
 )
 
 func joinAndQuoteMeta(list []string, sep string) string {
@@ -51,6 +71,8 @@ func joinAndQuoteMeta(list []string, sep string) string {
 
 type CommitParser interface {
 	Parse(rev string, processor Processor) ([]*types.Commit, error)
+	// ParseAuthors returns the normalized authors of all commits reachable from rev
+	ParseAuthors(rev string) ([]*types.CommitContributor, error)
 }
 
 type commitParser struct {
@@ -63,6 +85,9 @@ type commitParser struct {
 	reIssue   *regexp.Regexp
 	reNotes   *regexp.Regexp
 	reMention *regexp.Regexp
+	reTrailer *regexp.Regexp
+	mailmap   *Mailmap
+	overrides *types.CommitOverrides
 }
 
 func NewCommitParser(client gitcmd.Client, config *types.ChangelogConfig) CommitParser {
@@ -82,7 +107,27 @@ func NewCommitParser(client gitcmd.Client, config *types.ChangelogConfig) Commit
 		reIssue:   regexp.MustCompile("(?:" + joinedIssuePrefix + ")(\\d+)"),
 		reNotes:   regexp.MustCompile("^(?i)\\s*(" + joinedNoteKeywords + ")[:\\s]+(.*)"),
 		reMention: regexp.MustCompile("@([\\w-]+)"),
+		mailmap:   newCommitMailmap(config),
+		overrides: config.Overrides,
+		reTrailer: regexp.MustCompile("^(?i)\\s*(" + strings.Join([]string{coAuthoredByTrailer, signedOffByTrailer, reviewedByTrailer}, "|") + "):\\s*(.*?)\\s*(?:<([^>]*)>)?\\s*$"),
+	}
+}
+
+// newCommitMailmap loads the `.mailmap` of repository and the global mailmap file
+func newCommitMailmap(config *types.ChangelogConfig) *Mailmap {
+	mailmap := NewMailmap()
+
+	if err := mailmap.LoadFile(filepath.Join(config.WorkingDir, ".mailmap"), true); err != nil {
+		log.Warningf("load repository mailmap: %v", err)
 	}
+
+	if config.Options.MailmapFile != "" {
+		if err := mailmap.LoadFile(config.Options.MailmapFile, false); err != nil {
+			log.Warningf("load global mailmap: %v", err)
+		}
+	}
+
+	return mailmap
 }
 
 func (p *commitParser) Parse(rev string, processor Processor) ([]*types.Commit, error) {
@@ -90,7 +135,20 @@ func (p *commitParser) Parse(rev string, processor Processor) ([]*types.Commit,
 	if p.config.Options.NoMerges {
 		args = append(args, "--no-merges")
 	}
-	args = append(args, rev, "--no-decorate", "--pretty="+logFormat)
+	format := logFormat
+	if p.config.Options.NotesRef != "" {
+		args = append(args, "--no-notes", "--notes="+p.config.Options.NotesRef)
+		format += delimiter + notesFormat
+	}
+	if p.config.Options.DiffStat {
+		args = append(args, "--numstat")
+		format += delimiter + filesFormat
+	} else if len(p.config.Components) > 0 {
+		args = append(args, "--name-only")
+		format += delimiter + filesFormat
+	}
+	args = append(args, rev, "--no-decorate", "--pretty="+format)
+	args = p.appendPaths(args)
 	out, err := p.client.Exec(
 		"log",
 		args...,
@@ -102,10 +160,28 @@ func (p *commitParser) Parse(rev string, processor Processor) ([]*types.Commit,
 
 	lines := strings.Split(out, separator)
 	lines = lines[1:]
-	commits := make([]*types.Commit, len(lines))
+	commits := make([]*types.Commit, 0, len(lines))
 
-	for i, line := range lines {
-		commit := p.parseCommit(line)
+	for _, line := range lines {
+		commit, noteOverride := p.parseCommit(line)
+
+		if noteOverride != nil {
+			commit = noteOverride.Apply(commit)
+			if commit == nil {
+				continue
+			}
+		}
+
+		if override := p.overrides.Match(commit); override != nil {
+			commit = override.Apply(commit)
+			if commit == nil {
+				continue
+			}
+		}
+
+		if p.config.Options.ReleaseNoteBlocks && strings.EqualFold(commit.ReleaseNote, releaseNoteNone) {
+			continue
+		}
 
 		if processor != nil {
 			commit = processor.ProcessCommit(commit)
@@ -114,14 +190,52 @@ func (p *commitParser) Parse(rev string, processor Processor) ([]*types.Commit,
 			}
 		}
 
-		commits[i] = commit
+		commits = append(commits, commit)
 	}
 
 	return commits, nil
 }
 
-func (p *commitParser) parseCommit(input string) *types.Commit {
+// appendPaths restricts `git log` to the path specs of config
+func (p *commitParser) appendPaths(args []string) []string {
+	if len(p.config.Paths) == 0 {
+		return args
+	}
+	return append(append(args, "--"), p.config.Paths...)
+}
+
+func (p *commitParser) ParseAuthors(rev string) ([]*types.CommitContributor, error) {
+	out, err := p.client.Exec(
+		"log",
+		p.appendPaths([]string{rev, "--no-decorate", "--pretty=%an\t%ae"})...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []*types.CommitContributor{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		arr := strings.Split(line, "\t")
+		if len(arr) != 2 {
+			continue
+		}
+		author := &types.CommitContributor{}
+		author.Name, author.Email = p.mailmap.Map(arr[0], arr[1])
+		if seen[author.Key()] {
+			continue
+		}
+		seen[author.Key()] = true
+		authors = append(authors, author)
+	}
+
+	return authors, nil
+}
+
+// parseCommit parses the log of commit, the override of commit note is returned if any
+func (p *commitParser) parseCommit(input string) (*types.Commit, *types.CommitOverride) {
 	commit := &types.Commit{}
+	var override *types.CommitOverride
 	tokens := strings.Split(input, delimiter)
 
 	for _, token := range tokens {
@@ -140,13 +254,147 @@ func (p *commitParser) parseCommit(input string) *types.Commit {
 			p.processHeader(commit, value)
 		case bodyField:
 			p.processBody(commit, value)
+		case notesField:
+			override = p.parseNoteOverride(commit, value)
+		case filesField:
+			p.processFiles(commit, value)
 		}
 	}
 
+	p.normalizeIdentities(commit)
+
 	commit.Refs = p.uniqRefs(commit.Refs)
 	commit.Mentions = p.uniqMentions(commit.Mentions)
+	commit.CoAuthors = p.uniqContributors(commit.CoAuthors)
+	commit.SignedOffBy = p.uniqContributors(commit.SignedOffBy)
+	commit.ReviewedBy = p.uniqContributors(commit.ReviewedBy)
+
+	return commit, override
+}
+
+// processFiles parses files of `--name-only` or `--numstat` and attributes commit to the components
+func (p *commitParser) processFiles(commit *types.Commit, input string) {
+	commit.Files = []string{}
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if !p.config.Options.DiffStat {
+			commit.Files = append(commit.Files, line)
+			continue
+		}
+
+		stat := parseNumstat(line)
+		if stat == nil {
+			continue
+		}
+		commit.Files = append(commit.Files, stat.Path)
+		commit.FileStats = append(commit.FileStats, stat)
+		commit.Insertions += stat.Insertions
+		commit.Deletions += stat.Deletions
+	}
+
+	commit.Components = []string{}
+	for _, component := range p.config.Components {
+		if matchComponent(component, commit.Files) {
+			commit.Components = append(commit.Components, component.Name)
+		}
+	}
+}
+
+// parseNumstat parses line of `--numstat`, e.g. `10\t2\tpkg/{old => new}/main.go`, the path is the new one of rename
+func parseNumstat(line string) *types.CommitFileStat {
+	fields := strings.SplitN(line, "\t", 3)
+	if len(fields) != 3 {
+		return nil
+	}
+
+	stat := &types.CommitFileStat{
+		Path: numstatPath(fields[2]),
+	}
+	if fields[0] == "-" && fields[1] == "-" {
+		stat.Binary = true
+		return stat
+	}
+	stat.Insertions, _ = strconv.Atoi(fields[0])
+	stat.Deletions, _ = strconv.Atoi(fields[1])
+	return stat
+}
 
-	return commit
+func numstatPath(input string) string {
+	start := strings.Index(input, "{")
+	end := strings.Index(input, "}")
+	if start >= 0 && end > start {
+		if arr := strings.SplitN(input[start+1:end], " => ", 2); len(arr) == 2 {
+			return path.Clean(input[:start] + arr[1] + input[end+1:])
+		}
+	}
+	if arr := strings.SplitN(input, " => ", 2); len(arr) == 2 {
+		return arr[1]
+	}
+	return input
+}
+
+func matchComponent(component *types.ComponentConfig, files []string) bool {
+	for _, file := range files {
+		for _, glob := range component.Paths {
+			if matchPathGlob(glob, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches file by glob of `path.Match`, the directory matches all files in it
+func matchPathGlob(glob string, file string) bool {
+	glob = strings.TrimSuffix(strings.TrimSuffix(glob, "**"), "/")
+	if glob == "" {
+		return true
+	}
+	if ok, _ := path.Match(glob, file); ok {
+		return true
+	}
+
+	dir := file
+	for {
+		dir = path.Dir(dir)
+		if dir == "." || dir == "/" {
+			return false
+		}
+		if ok, _ := path.Match(glob, dir); ok {
+			return true
+		}
+	}
+}
+
+func (p *commitParser) parseNoteOverride(commit *types.Commit, input string) *types.CommitOverride {
+	if input == "" {
+		return nil
+	}
+
+	override, err := ParseCommitNoteOverride(input)
+	if err != nil {
+		log.Warningf("parse note %s of commit %s: %v", p.config.Options.NotesRef, commit.Hash.Short, err)
+		p.config.NoteErrors.Add(p.config.WorkingDir, commit.Hash.Short, err)
+		return nil
+	}
+	return override
+}
+
+// normalizeIdentities maps author, committer and trailer identities by mailmap
+func (p *commitParser) normalizeIdentities(commit *types.Commit) {
+	if commit.Author != nil {
+		commit.Author.Name, commit.Author.Email = p.mailmap.Map(commit.Author.Name, commit.Author.Email)
+	}
+	if commit.Committer != nil {
+		commit.Committer.Name, commit.Committer.Email = p.mailmap.Map(commit.Committer.Name, commit.Committer.Email)
+	}
+	for _, contributors := range [][]*types.CommitContributor{commit.CoAuthors, commit.SignedOffBy, commit.ReviewedBy} {
+		for _, c := range contributors {
+			c.Name, c.Email = p.mailmap.Map(c.Name, c.Email)
+		}
+	}
 }
 
 func (p *commitParser) parseHash(input string) *types.CommitHash {
@@ -228,11 +476,31 @@ func (p *commitParser) processBody(commit *types.Commit, input string) {
 	inNote := false
 	fenceDetector := newMdFenceDetector()
 	lines := strings.Split(input, "\n")
+	releaseNotes := []string{}
 
 	for _, line := range lines {
+		inReleaseNote := fenceDetector.Lang() == releaseNoteLang
 		fenceDetector.Update(line)
 
+		if p.config.Options.ReleaseNoteBlocks && inReleaseNote {
+			if fenceDetector.InCodeblock() {
+				releaseNotes = append(releaseNotes, line)
+			}
+			continue
+		}
+
 		if !fenceDetector.InCodeblock() {
+			if res := reChangeID.FindStringSubmatch(line); len(res) > 0 {
+				inNote = false
+				commit.ChangeID = res[1]
+				continue
+			}
+
+			if p.processTrailer(commit, line) {
+				inNote = false
+				continue
+			}
+
 			refs := p.parseRefs(line)
 			if len(refs) > 0 {
 				inNote = false
@@ -263,6 +531,35 @@ func (p *commitParser) processBody(commit *types.Commit, input string) {
 	}
 
 	p.trimSpaceInNotes(commit)
+	commit.ReleaseNote = strings.TrimSpace(strings.Join(releaseNotes, "\n"))
+}
+
+// processTrailer parses `Co-authored-by`, `Signed-off-by` and `Reviewed-by` trailers,
+// it reports whether the line is a trailer
+func (p *commitParser) processTrailer(commit *types.Commit, line string) bool {
+	res := p.reTrailer.FindStringSubmatch(line)
+	if len(res) == 0 {
+		return false
+	}
+
+	contributor := &types.CommitContributor{
+		Name:  res[2],
+		Email: res[3],
+	}
+	if contributor.Name == "" && contributor.Email == "" {
+		return false
+	}
+
+	switch strings.ToLower(res[1]) {
+	case coAuthoredByTrailer:
+		commit.CoAuthors = append(commit.CoAuthors, contributor)
+	case signedOffByTrailer:
+		commit.SignedOffBy = append(commit.SignedOffBy, contributor)
+	case reviewedByTrailer:
+		commit.ReviewedBy = append(commit.ReviewedBy, contributor)
+	}
+
+	return true
 }
 
 func (*commitParser) trimSpaceInNotes(commit *types.Commit) {
@@ -353,6 +650,24 @@ func (p *commitParser) uniqMentions(mentions []string) []string {
 	return arr
 }
 
+func (p *commitParser) uniqContributors(contributors []*types.CommitContributor) []*types.CommitContributor {
+	arr := []*types.CommitContributor{}
+
+	for _, contributor := range contributors {
+		exist := false
+		for _, c := range arr {
+			if contributor.Key() == c.Key() {
+				exist = true
+			}
+		}
+		if !exist {
+			arr = append(arr, contributor)
+		}
+	}
+
+	return arr
+}
+
 var (
 	fenceTypes = []string{
 		"```",
@@ -364,6 +679,8 @@ var (
 
 type mdFenceDetector struct {
 	fence int
+	// info string of the opening fence, e.g. `release-note` of ```release-note
+	lang string
 }
 
 func newMdFenceDetector() *mdFenceDetector {
@@ -376,16 +693,25 @@ func (d *mdFenceDetector) InCodeblock() bool {
 	return d.fence > -1
 }
 
+// Lang returns the info string of current fenced code block
+func (d *mdFenceDetector) Lang() string {
+	return d.lang
+}
+
 func (d *mdFenceDetector) Update(input string) {
 	for i, s := range fenceTypes {
 		if d.fence < 0 {
 			if strings.Index(input, s) == 0 {
 				d.fence = i
+				if strings.TrimSpace(s) != "" {
+					d.lang = strings.TrimSpace(input[len(s):])
+				}
 				break
 			}
 		} else {
 			if strings.Index(input, s) == 0 && i == d.fence {
 				d.fence = -1
+				d.lang = ""
 				break
 			}
 		}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -102,13 +103,16 @@ func TestCommitParserParse(t *testing.T) {
 					Source: "",
 				},
 			},
-			Notes:    []*types.CommitNote{},
-			Mentions: []string{},
-			Header:   "feat(*): Add new feature #123",
-			Type:     "feat",
-			Scope:    "*",
-			Subject:  "Add new feature #123",
-			Body:     "",
+			Notes:       []*types.CommitNote{},
+			Mentions:    []string{},
+			CoAuthors:   []*types.CommitContributor{},
+			SignedOffBy: []*types.CommitContributor{},
+			ReviewedBy:  []*types.CommitContributor{},
+			Header:      "feat(*): Add new feature #123",
+			Type:        "feat",
+			Scope:       "*",
+			Subject:     "Add new feature #123",
+			Body:        "",
 		},
 		{
 			Hash: &types.CommitHash{
@@ -153,11 +157,14 @@ func TestCommitParserParse(t *testing.T) {
 					Body:  "This is breaking point message.",
 				},
 			},
-			Mentions: []string{},
-			Header:   "Merge pull request #3 from username/branchname",
-			Type:     "",
-			Scope:    "",
-			Subject:  "",
+			Mentions:    []string{},
+			CoAuthors:   []*types.CommitContributor{},
+			SignedOffBy: []*types.CommitContributor{},
+			ReviewedBy:  []*types.CommitContributor{},
+			Header:      "Merge pull request #3 from username/branchname",
+			Type:        "",
+			Scope:       "",
+			Subject:     "",
 			Body: `This is body message.
 
 Fixes #3
@@ -190,10 +197,13 @@ BREAKING CHANGE: This is breaking point message.`,
 				"hogefuga",
 				"FooBarBaz",
 			},
-			Header:  "fix(controller): Fix cors configure",
-			Type:    "fix",
-			Scope:   "controller",
-			Subject: "Fix cors configure",
+			CoAuthors:   []*types.CommitContributor{},
+			SignedOffBy: []*types.CommitContributor{},
+			ReviewedBy:  []*types.CommitContributor{},
+			Header:      "fix(controller): Fix cors configure",
+			Type:        "fix",
+			Scope:       "controller",
+			Subject:     "Fix cors configure",
 			Body: `Has mention body
 
 @tsuyoshiwada
@@ -251,11 +261,14 @@ class MyController extends Controller {
 %s`, "```", "```"),
 				},
 			},
-			Mentions: []string{},
-			Header:   "fix(model): Remove hoge attributes",
-			Type:     "fix",
-			Scope:    "model",
-			Subject:  "Remove hoge attributes",
+			Mentions:    []string{},
+			CoAuthors:   []*types.CommitContributor{},
+			SignedOffBy: []*types.CommitContributor{},
+			ReviewedBy:  []*types.CommitContributor{},
+			Header:      "fix(model): Remove hoge attributes",
+			Type:        "fix",
+			Scope:       "model",
+			Subject:     "Remove hoge attributes",
 			Body: fmt.Sprintf(`This mixed body message.
 
 BREAKING CHANGE:
@@ -299,14 +312,262 @@ Closes username/repository#456`, "```", "```"),
 			Revert: &types.CommitRevert{
 				Header: "fix(core): commit message",
 			},
-			Refs:     []*types.CommitRef{},
-			Notes:    []*types.CommitNote{},
-			Mentions: []string{},
-			Header:   "Revert \"fix(core): commit message\"",
-			Type:     "",
-			Scope:    "",
-			Subject:  "",
-			Body:     "This reverts commit f755db78dcdf461dc42e709b3ab728ceba353d1d.",
+			Refs:        []*types.CommitRef{},
+			Notes:       []*types.CommitNote{},
+			Mentions:    []string{},
+			CoAuthors:   []*types.CommitContributor{},
+			SignedOffBy: []*types.CommitContributor{},
+			ReviewedBy:  []*types.CommitContributor{},
+			Header:      "Revert \"fix(core): commit message\"",
+			Type:        "",
+			Scope:       "",
+			Subject:     "",
+			Body:        "This reverts commit f755db78dcdf461dc42e709b3ab728ceba353d1d.",
 		},
 	}, commits)
 }
+
+func TestCommitParserTrailers(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Add pair feature",
+				`BODY:This is body message.
+
+BREAKING CHANGE: This is breaking point message.
+Co-authored-by: Foo Bar <foo@example.com>
+co-authored-by: Foo <FOO@example.com>
+Co-authored-by: Hoge <hoge@example.com>
+Signed-off-by: tsuyoshi wada <mail@example.com>
+Reviewed-by: Fuga <fuga@example.com>`,
+			}, delimiter), nil
+		},
+	}
+
+	parser := NewCommitParser(mock, &types.ChangelogConfig{
+		Options: &types.ChangelogConfigOptions{
+			HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+			HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+			NoteKeywords:      []string{"BREAKING CHANGE"},
+		},
+	})
+
+	commits, err := parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Len(commits, 1)
+
+	commit := commits[0]
+	assert.Equal([]*types.CommitNote{
+		{
+			Title: "BREAKING CHANGE",
+			Body:  "This is breaking point message.",
+		},
+	}, commit.Notes)
+	assert.Equal([]string{}, commit.Mentions)
+	assert.Equal([]*types.CommitContributor{
+		{Name: "Foo Bar", Email: "foo@example.com"},
+		{Name: "Hoge", Email: "hoge@example.com"},
+	}, commit.CoAuthors)
+	assert.Equal([]*types.CommitContributor{
+		{Name: "tsuyoshi wada", Email: "mail@example.com"},
+	}, commit.SignedOffBy)
+	assert.Equal([]*types.CommitContributor{
+		{Name: "Fuga", Email: "fuga@example.com"},
+	}, commit.ReviewedBy)
+	assert.Equal([]*types.CommitContributor{
+		{Name: "tsuyoshi wada", Email: "mail@example.com"},
+		{Name: "Foo Bar", Email: "foo@example.com"},
+		{Name: "Hoge", Email: "hoge@example.com"},
+	}, commit.Contributors())
+	assert.Equal([]*types.CommitContributor{
+		{Name: "Fuga", Email: "fuga@example.com"},
+	}, commit.Reviewers())
+}
+
+func TestCommitParserReleaseNoteBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Add new feature (#12)",
+				"BODY:This is body message.\n\n```release-note\nSupport new feature.\nIt fixes #3\n```\n\n```\ncode\n```",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:14ef0b6d386c5432af9292eab3c8314fa3001bc7\t14ef0b6d",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:chore(ci): Update ci",
+				"BODY:```release-note\nNONE\n```",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:809a8280ffd0dadb0f4e7ba9fc835e63c37d6af6\t809a8280",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:fix(core): Fix bug",
+				"BODY:Fixes #4",
+			}, delimiter), nil
+		},
+	}
+
+	opts := &types.ChangelogConfigOptions{
+		HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+		HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+		IssuePrefix:       []string{"#"},
+		RefActions:        []string{"Fixes"},
+	}
+	parser := NewCommitParser(mock, &types.ChangelogConfig{Options: opts})
+
+	commits, err := parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Len(commits, 3)
+	assert.Equal("", commits[0].ReleaseNote)
+
+	opts.ReleaseNoteBlocks = true
+	parser = NewCommitParser(mock, &types.ChangelogConfig{Options: opts})
+
+	commits, err = parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Len(commits, 2)
+
+	commit := commits[0]
+	assert.Equal("Support new feature.\nIt fixes #3", commit.ReleaseNote)
+	assert.Equal("This is body message.\n\n```release-note\nSupport new feature.\nIt fixes #3\n```\n\n```\ncode\n```", commit.Body)
+
+	commit = commits[1]
+	assert.Equal("", commit.ReleaseNote)
+	assert.Equal("Fix bug", commit.Subject)
+}
+
+func TestCommitParserComponents(t *testing.T) {
+	assert := assert.New(t)
+
+	var logArgs []string
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			logArgs = args
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:fix(host): Fix host and web",
+				"BODY:This is body message.\n",
+				"FILES:\n\npkg/hostman/host.go\nweb/src/app.js\n",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:14ef0b6d386c5432af9292eab3c8314fa3001bc7\t14ef0b6d",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:docs: Update README",
+				"BODY:",
+				"FILES:\n\nREADME.md\n",
+			}, delimiter), nil
+		},
+	}
+
+	parser := NewCommitParser(mock, &types.ChangelogConfig{
+		Paths: []string{"pkg", "web", "README.md"},
+		Components: []*types.ComponentConfig{
+			{Name: "compute", Paths: []string{"pkg/compute"}},
+			{Name: "host", Paths: []string{"pkg/hostman/", "cmd/host*"}},
+			{Name: "web", Paths: []string{"web/**"}},
+		},
+		Options: &types.ChangelogConfigOptions{
+			HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+			HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+		},
+	})
+
+	commits, err := parser.Parse("v1.0.0..v2.0.0", nil)
+	assert.Nil(err)
+	assert.Contains(logArgs, "--name-only")
+	assert.Equal([]string{"--", "pkg", "web", "README.md"}, logArgs[len(logArgs)-4:])
+	assert.Len(commits, 2)
+
+	assert.Equal("This is body message.", commits[0].Body)
+	assert.Equal([]string{"pkg/hostman/host.go", "web/src/app.js"}, commits[0].Files)
+	assert.Equal([]string{"host", "web"}, commits[0].Components)
+	assert.Equal([]string{"README.md"}, commits[1].Files)
+	assert.Equal([]string{}, commits[1].Components)
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		glob string
+		file string
+		want bool
+	}{
+		{"pkg/compute", "pkg/compute/models/guests.go", true},
+		{"pkg/compute", "pkg/computer/main.go", false},
+		{"pkg/compute/", "pkg/compute/main.go", true},
+		{"web/**", "web/src/app.js", true},
+		{"**", "README.md", true},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"cmd/*", "cmd/region/main.go", true},
+		{"pkg/*/models", "pkg/compute/models/guests.go", true},
+		{"pkg/*/models", "pkg/compute/main.go", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.want, matchPathGlob(tt.glob, tt.file), "%s %s", tt.glob, tt.file)
+	}
+}
+
+func TestCommitParserDiffStat(t *testing.T) {
+	assert := assert.New(t)
+
+	var logArgs []string
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			logArgs = args
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:fix(host): Fix host and web",
+				"BODY:",
+				"FILES:\n\n10\t2\tpkg/hostman/host.go\n-\t-\tweb/logo.png\n3\t3\tpkg/{compute => region}/main.go\n0\t0\told.go => web/new.go\n",
+			}, delimiter), nil
+		},
+	}
+
+	parser := NewCommitParser(mock, &types.ChangelogConfig{
+		Components: []*types.ComponentConfig{
+			{Name: "region", Paths: []string{"pkg/region"}},
+			{Name: "web", Paths: []string{"web"}},
+		},
+		Options: &types.ChangelogConfigOptions{
+			HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+			HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+			DiffStat:          true,
+		},
+	})
+
+	commits, err := parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Contains(logArgs, "--numstat")
+	assert.NotContains(logArgs, "--name-only")
+	assert.Len(commits, 1)
+
+	commit := commits[0]
+	assert.Equal([]string{"pkg/hostman/host.go", "web/logo.png", "pkg/region/main.go", "web/new.go"}, commit.Files)
+	assert.Equal([]*types.CommitFileStat{
+		{Path: "pkg/hostman/host.go", Insertions: 10, Deletions: 2},
+		{Path: "web/logo.png", Binary: true},
+		{Path: "pkg/region/main.go", Insertions: 3, Deletions: 3},
+		{Path: "web/new.go"},
+	}, commit.FileStats)
+	assert.Equal(13, commit.Insertions)
+	assert.Equal(5, commit.Deletions)
+	assert.Equal([]string{"region", "web"}, commit.Components)
+
+	assert.Equal("pkg/main.go", numstatPath("pkg/{compute => }/main.go"))
+	assert.Equal("pkg/compute/main.go", numstatPath("pkg/{ => compute}/main.go"))
+}
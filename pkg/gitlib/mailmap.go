@@ -0,0 +1,127 @@
+package gitlib
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"yunion.io/x/pkg/errors"
+)
+
+var (
+	reMailmapIdentity = regexp.MustCompile(`\s*([^<]*?)\s*<([^>]*)>`)
+)
+
+type mailmapEntry struct {
+	name  string
+	email string
+}
+
+// Mailmap maps commit identities to canonical ones, the format is the same as git `.mailmap`
+//
+//	Proper Name <commit@email.xx>
+//	<proper@email.xx> <commit@email.xx>
+//	Proper Name <proper@email.xx> <commit@email.xx>
+//	Proper Name <proper@email.xx> Commit Name <commit@email.xx>
+type Mailmap struct {
+	// entries keyed by commit email, then commit name ("" matches any name)
+	entries map[string]map[string]*mailmapEntry
+}
+
+func NewMailmap() *Mailmap {
+	return &Mailmap{
+		entries: make(map[string]map[string]*mailmapEntry),
+	}
+}
+
+// LoadFile reads mailmap entries from file, entries loaded later take precedence.
+// A missing file is ignored when `optional` is true
+func (m *Mailmap) LoadFile(fileName string, optional bool) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "open mailmap %q", fileName)
+	}
+	defer f.Close()
+
+	if err := m.Load(f); err != nil {
+		return errors.Wrapf(err, "read mailmap %q", fileName)
+	}
+	return nil
+}
+
+// Load reads mailmap entries from reader
+func (m *Mailmap) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m.parseLine(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// parseLine parses the entry like git, the line starting with `#` is comment
+// and the text after the identities is ignored
+func (m *Mailmap) parseLine(line string) {
+	if strings.HasPrefix(line, "#") {
+		return
+	}
+
+	res := reMailmapIdentity.FindAllStringSubmatch(line, 2)
+	if len(res) == 0 {
+		return
+	}
+
+	proper := &mailmapEntry{
+		name:  res[0][1],
+		email: res[0][2],
+	}
+	commitName := ""
+	commitEmail := proper.email
+	if len(res) == 2 {
+		commitName = res[1][1]
+		commitEmail = res[1][2]
+	} else {
+		// `Proper Name <commit@email.xx>` only replaces the name
+		proper.email = ""
+	}
+
+	emailKey := strings.ToLower(commitEmail)
+	names, ok := m.entries[emailKey]
+	if !ok {
+		names = make(map[string]*mailmapEntry)
+		m.entries[emailKey] = names
+	}
+	names[strings.ToLower(commitName)] = proper
+}
+
+// Map returns the canonical name and email of the identity
+func (m *Mailmap) Map(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+
+	names, ok := m.entries[strings.ToLower(email)]
+	if !ok {
+		return name, email
+	}
+
+	entry, ok := names[strings.ToLower(name)]
+	if !ok {
+		entry, ok = names[""]
+		if !ok {
+			return name, email
+		}
+	}
+
+	if entry.name != "" {
+		name = entry.name
+	}
+	if entry.email != "" {
+		email = entry.email
+	}
+	return name, email
+}
@@ -0,0 +1,56 @@
+package gitlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailmap(t *testing.T) {
+	assert := assert.New(t)
+
+	mailmap := NewMailmap()
+	err := mailmap.Load(strings.NewReader(`# comment
+Proper Name <proper@example.com>
+<corp@example.com> <personal@example.com>
+Foo Bar <foo@example.com> <foo@users.noreply.github.com> # trailing comment
+Hoge <hoge@example.com> hoge <shared@example.com>
+C# Team <team@example.com> <c#team@example.com>
+#Commented Out <commented@example.com>
+`))
+	assert.Nil(err)
+
+	for _, tt := range []struct {
+		name      string
+		email     string
+		wantName  string
+		wantEmail string
+	}{
+		{"proper", "Proper@example.com", "Proper Name", "Proper@example.com"},
+		{"someone", "personal@example.com", "someone", "corp@example.com"},
+		{"foo", "foo@users.noreply.github.com", "Foo Bar", "foo@example.com"},
+		{"HOGE", "shared@example.com", "Hoge", "hoge@example.com"},
+		{"fuga", "shared@example.com", "fuga", "shared@example.com"},
+		{"unknown", "unknown@example.com", "unknown", "unknown@example.com"},
+		// `#` is a comment only at the start of line
+		{"csharp", "C#Team@example.com", "C# Team", "team@example.com"},
+		{"commented", "commented@example.com", "commented", "commented@example.com"},
+	} {
+		name, email := mailmap.Map(tt.name, tt.email)
+		assert.Equal(tt.wantName, name)
+		assert.Equal(tt.wantEmail, email)
+	}
+
+	// later entries take precedence
+	err = mailmap.Load(strings.NewReader(`Corp Name <corp2@example.com> <personal@example.com>`))
+	assert.Nil(err)
+	name, email := mailmap.Map("someone", "personal@example.com")
+	assert.Equal("Corp Name", name)
+	assert.Equal("corp2@example.com", email)
+
+	var nilMailmap *Mailmap
+	name, email = nilMailmap.Map("someone", "personal@example.com")
+	assert.Equal("someone", name)
+	assert.Equal("personal@example.com", email)
+}
@@ -0,0 +1,58 @@
+package gitlib
+
+import (
+	"io/ioutil"
+
+	"yunion.io/x/jsonutils"
+	"yunion.io/x/pkg/errors"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+// LoadCommitOverrides reads and validates the yaml overrides file
+func LoadCommitOverrides(fileName string) (*types.CommitOverrides, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read overrides %q", fileName)
+	}
+
+	return ParseCommitOverrides(string(content))
+}
+
+// ParseCommitOverrides parses and validates the yaml overrides content
+func ParseCommitOverrides(content string) (*types.CommitOverrides, error) {
+	jObj, err := jsonutils.ParseYAML(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse overrides yaml content")
+	}
+
+	overrides := new(types.CommitOverrides)
+	if err := jObj.Unmarshal(overrides); err != nil {
+		return nil, errors.Wrap(err, "unmarshal overrides")
+	}
+	if err := overrides.Validate(); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// ParseCommitNoteOverride parses the git note of commit, it's yaml of override fields without commit and changeId
+//
+//	type: fix
+//	subject: fix typo of subject
+//	releaseNote: Fix the typo of subject
+//	hide: false
+func ParseCommitNoteOverride(content string) (*types.CommitOverride, error) {
+	jObj, err := jsonutils.ParseYAML(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse note yaml content")
+	}
+
+	override := new(types.CommitOverride)
+	if err := jObj.Unmarshal(override); err != nil {
+		return nil, errors.Wrap(err, "unmarshal note")
+	}
+
+	return override, nil
+}
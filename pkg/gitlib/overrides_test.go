@@ -0,0 +1,196 @@
+package gitlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func TestParseCommitOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	overrides, err := ParseCommitOverrides(`
+overrides:
+- commit: 65cf1add
+  hide: true
+- changeId: I8473b95934b5732ac55d26311a706c9c2bde9940
+  type: fix
+  scope: host
+`)
+	assert.Nil(err)
+	assert.Len(overrides.Overrides, 2)
+	assert.True(overrides.Overrides[0].Hide)
+	assert.Equal("fix", overrides.Overrides[1].Type)
+
+	_, err = ParseCommitOverrides("overrides:\n- type: fix\n")
+	assert.NotNil(err)
+
+	_, err = ParseCommitOverrides("overrides:\n- commit: 65cf\n")
+	assert.NotNil(err)
+}
+
+func TestCommitParserOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Add hidden feature",
+				"BODY:",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:14ef0b6d386c5432af9292eab3c8314fa3001bc7\t14ef0b6d",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Fix typo",
+				`BODY:This is body message.
+
+Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940`,
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:809a8280ffd0dadb0f4e7ba9fc835e63c37d6af6\t809a8280",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:docs: Update README",
+				"BODY:",
+			}, delimiter), nil
+		},
+	}
+
+	overrides := &types.CommitOverrides{
+		Overrides: []*types.CommitOverride{
+			{Commit: "65CF1ADD", Hide: true},
+			{ChangeID: "I8473b95934b5732ac55d26311a706c9c2bde9940", Type: "fix", Subject: "Correct typo"},
+			{Commit: "1234567", Hide: true},
+		},
+	}
+
+	parser := NewCommitParser(mock, &types.ChangelogConfig{
+		Overrides: overrides,
+		Options: &types.ChangelogConfigOptions{
+			HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+			HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+		},
+	})
+
+	commits, err := parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Len(commits, 2)
+
+	commit := commits[0]
+	assert.Equal("I8473b95934b5732ac55d26311a706c9c2bde9940", commit.ChangeID)
+	assert.Equal("fix", commit.Type)
+	assert.Equal("core", commit.Scope)
+	assert.Equal("Correct typo", commit.Subject)
+	assert.Equal("fix(core): Correct typo", commit.Header)
+
+	assert.Equal("docs", commits[1].Type)
+	assert.Equal("", commits[1].ChangeID)
+
+	assert.Equal([]*types.CommitOverride{overrides.Overrides[2]}, overrides.Unused())
+}
+
+func TestCommitParserNoteOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	var logArgs []string
+	mock := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			logArgs = args
+			return separator + strings.Join([]string{
+				"HASH:65cf1add9735dcc4810dda3312b0792236c97c4e\t65cf1add",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Add hidden feature",
+				"BODY:",
+				"NOTES:hide: true\n",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:14ef0b6d386c5432af9292eab3c8314fa3001bc7\t14ef0b6d",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:feat(core): Fix typo",
+				"BODY:",
+				`NOTES:type: fix
+subject: Correct typo
+releaseNote: Correct the typo of core
+notes:
+- title: BREAKING CHANGE
+  body: The API is changed.
+`,
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:809a8280ffd0dadb0f4e7ba9fc835e63c37d6af6\t809a8280",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:docs: Update README",
+				"BODY:",
+				"NOTES:",
+			}, delimiter) + separator + strings.Join([]string{
+				"HASH:a0d0a9b6c8c3bd0d5e6e1a8e5e6ccf3f9ac2e4a1\ta0d0a9b6",
+				"AUTHOR:tsuyoshi wada\tmail@example.com\t1514808000",
+				"COMMITTER:tsuyoshi wada\tmail@example.com\t1514808000",
+				"SUBJECT:fix: Keep malformed note",
+				"BODY:",
+				"NOTES:type: [fix\n",
+			}, delimiter), nil
+		},
+	}
+
+	noteErrs := new(types.CommitNoteErrors)
+	parser := NewCommitParser(mock, &types.ChangelogConfig{
+		WorkingDir: "/repo",
+		NoteErrors: noteErrs,
+		Options: &types.ChangelogConfigOptions{
+			HeaderPattern:     "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$",
+			HeaderPatternMaps: []string{"Type", "Scope", "Subject"},
+			NotesRef:          "refs/notes/changelog",
+		},
+	})
+
+	commits, err := parser.Parse("HEAD", nil)
+	assert.Nil(err)
+	assert.Contains(logArgs, "--notes=refs/notes/changelog")
+	assert.Len(commits, 3)
+
+	commit := commits[0]
+	assert.Equal("fix(core): Correct typo", commit.Header)
+	assert.Equal("Correct the typo of core", commit.ReleaseNote)
+	assert.Equal([]*types.CommitNote{
+		{Title: "BREAKING CHANGE", Body: "The API is changed."},
+	}, commit.Notes)
+
+	assert.Equal("docs: Update README", commits[1].Header)
+
+	// malformed note keeps the commit and is collected for lint
+	assert.Equal("fix: Keep malformed note", commits[2].Header)
+	noteErrors := noteErrs.Errors()
+	assert.Len(noteErrors, 1)
+	assert.True(strings.HasPrefix(noteErrors[0], "/repo: commit a0d0a9b6: "))
+}
+
+func TestCommitOverrideApplyReleaseNote(t *testing.T) {
+	assert := assert.New(t)
+
+	newCommit := func() *types.Commit {
+		return &types.Commit{
+			Type:        "fix",
+			Subject:     "Fix typo",
+			ReleaseNote: "Fix the typo of body",
+		}
+	}
+
+	// overridden subject is not shadowed by release note of body
+	commit := (&types.CommitOverride{Commit: "65cf1add", Subject: "Correct typo"}).Apply(newCommit())
+	assert.Equal("Correct typo", commit.Subject)
+	assert.Equal("", commit.ReleaseNote)
+
+	commit = (&types.CommitOverride{Commit: "65cf1add", Subject: "Correct typo", ReleaseNote: "Correct the typo"}).Apply(newCommit())
+	assert.Equal("Correct typo", commit.Subject)
+	assert.Equal("Correct the typo", commit.ReleaseNote)
+
+	commit = (&types.CommitOverride{Commit: "65cf1add", Type: "docs"}).Apply(newCommit())
+	assert.Equal("Fix the typo of body", commit.ReleaseNote)
+}
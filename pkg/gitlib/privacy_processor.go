@@ -0,0 +1,173 @@
+package gitlib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+const (
+	redactedText = "[REDACTED]"
+)
+
+var (
+	// e.g. `12345+username@users.noreply.github.com`
+	reNoReplyEmail = regexp.MustCompile(`^(?:\d+\+)?([\w-]+)@users\.noreply\.`)
+)
+
+// ChainProcessor runs processors in order, the commit is dropped once a processor returns `nil`
+type ChainProcessor []Processor
+
+// Bootstrap ...
+func (ps ChainProcessor) Bootstrap(config *types.ChangelogConfig) {
+	for _, p := range ps {
+		p.Bootstrap(config)
+	}
+}
+
+// ProcessCommit ...
+func (ps ChainProcessor) ProcessCommit(commit *types.Commit) *types.Commit {
+	for _, p := range ps {
+		commit = p.ProcessCommit(commit)
+		if commit == nil {
+			return nil
+		}
+	}
+	return commit
+}
+
+// ProcessIdentity runs the processors implementing `IdentityProcessor`
+func (ps ChainProcessor) ProcessIdentity(contributor *types.CommitContributor) {
+	for _, p := range ps {
+		if ip, ok := p.(IdentityProcessor); ok {
+			ip.ProcessIdentity(contributor)
+		}
+	}
+}
+
+// PrivacyProcessor hides personal data before publishing CHANGELOG
+//
+// The following processing is performed
+//   - Redact the text matched by `RedactPatterns` in header, subject, body and notes
+//   - Render author, committer and trailer emails according to `EmailPolicy`
+type PrivacyProcessor struct {
+	Host           string // Host name used for profile link. Note: You must include the protocol (e.g. "https://github.com")
+	EmailPolicy    string
+	Profiles       map[string]string
+	RedactPatterns []string
+	reRedacts      []*regexp.Regexp
+	profiles       map[string]string
+}
+
+// NewPrivacyProcessor returns `nil` if nothing need to be hidden by output config
+func NewPrivacyProcessor(config *types.GlobalChangelogOutConfig) *PrivacyProcessor {
+	if config == nil {
+		return nil
+	}
+	if (config.EmailPolicy == "" || config.EmailPolicy == types.EmailPolicyShow) && len(config.RedactPatterns) == 0 {
+		return nil
+	}
+	return &PrivacyProcessor{
+		EmailPolicy:    config.EmailPolicy,
+		Profiles:       config.Profiles,
+		RedactPatterns: config.RedactPatterns,
+	}
+}
+
+// Bootstrap ...
+func (p *PrivacyProcessor) Bootstrap(config *types.ChangelogConfig) {
+	if p.Host == "" {
+		p.Host = "https://github.com"
+	} else {
+		p.Host = strings.TrimRight(p.Host, "/")
+	}
+
+	p.reRedacts = make([]*regexp.Regexp, len(p.RedactPatterns))
+	for i, pattern := range p.RedactPatterns {
+		p.reRedacts[i] = regexp.MustCompile(pattern)
+	}
+
+	p.profiles = make(map[string]string, len(p.Profiles))
+	for email, name := range p.Profiles {
+		p.profiles[strings.ToLower(email)] = name
+	}
+}
+
+// ProcessCommit ...
+func (p *PrivacyProcessor) ProcessCommit(commit *types.Commit) *types.Commit {
+	commit.Header = p.redact(commit.Header)
+	commit.Subject = p.redact(commit.Subject)
+	commit.Body = p.redact(commit.Body)
+	commit.ReleaseNote = p.redact(commit.ReleaseNote)
+
+	for _, note := range commit.Notes {
+		note.Body = p.redact(note.Body)
+	}
+
+	if commit.Revert != nil {
+		commit.Revert.Header = p.redact(commit.Revert.Header)
+	}
+
+	if commit.Author != nil {
+		commit.Author.Email, commit.Author.URL = p.processEmail(commit.Author.Email)
+	}
+	if commit.Committer != nil {
+		commit.Committer.Email, _ = p.processEmail(commit.Committer.Email)
+	}
+	for _, contributors := range [][]*types.CommitContributor{commit.CoAuthors, commit.SignedOffBy, commit.ReviewedBy} {
+		for _, c := range contributors {
+			p.ProcessIdentity(c)
+		}
+	}
+
+	return commit
+}
+
+// ProcessIdentity renders email of contributor according to `EmailPolicy`
+func (p *PrivacyProcessor) ProcessIdentity(contributor *types.CommitContributor) {
+	contributor.Email, contributor.URL = p.processEmail(contributor.Email)
+}
+
+func (p *PrivacyProcessor) redact(input string) string {
+	for _, re := range p.reRedacts {
+		input = re.ReplaceAllString(input, redactedText)
+	}
+	return input
+}
+
+// processEmail returns the email and profile link to render
+func (p *PrivacyProcessor) processEmail(email string) (string, string) {
+	switch p.EmailPolicy {
+	case types.EmailPolicyObfuscate:
+		return obfuscateEmail(email), ""
+	case types.EmailPolicyProfile:
+		if name := p.profileName(email); name != "" {
+			return "", fmt.Sprintf("%s/%s", p.Host, name)
+		}
+		return "", ""
+	case types.EmailPolicyOmit:
+		return "", ""
+	}
+	return email, ""
+}
+
+func (p *PrivacyProcessor) profileName(email string) string {
+	if name, ok := p.profiles[strings.ToLower(email)]; ok {
+		return name
+	}
+	res := reNoReplyEmail.FindStringSubmatch(email)
+	if len(res) == 0 {
+		return ""
+	}
+	return res[1]
+}
+
+// obfuscateEmail converts `foo@example.com` to `foo [at] example [dot] com`
+func obfuscateEmail(email string) string {
+	return strings.NewReplacer(
+		"@", " [at] ",
+		".", " [dot] ",
+	).Replace(email)
+}
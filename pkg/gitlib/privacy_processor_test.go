@@ -0,0 +1,86 @@
+package gitlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yunionio/git-tools/pkg/types"
+)
+
+func newPrivacyFixture() *types.Commit {
+	return &types.Commit{
+		Header:  "fix(host): connect to build.internal.example.com",
+		Subject: "connect to build.internal.example.com",
+		Body:    "reported by ops@internal.example.com",
+		// release note block of body
+		ReleaseNote: "connect to build.internal.example.com by default",
+		Notes: []*types.CommitNote{
+			{
+				Title: "BREAKING CHANGE",
+				Body:  "see https://wiki.internal.example.com/x",
+			},
+		},
+		Author: &types.CommitAuthor{
+			Name:  "foo",
+			Email: "foo@example.com",
+		},
+		Committer: &types.CommitCommitter{
+			Name:  "foo",
+			Email: "foo@example.com",
+		},
+		CoAuthors: []*types.CommitContributor{
+			{Name: "bar", Email: "123+bar@users.noreply.github.com"},
+		},
+	}
+}
+
+func TestPrivacyProcessor(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NewPrivacyProcessor(nil))
+	assert.Nil(NewPrivacyProcessor(&types.GlobalChangelogOutConfig{EmailPolicy: types.EmailPolicyShow}))
+
+	processor := NewPrivacyProcessor(&types.GlobalChangelogOutConfig{
+		RedactPatterns: []string{
+			`[\w\.-]+@internal\.example\.com`,
+			`[\w\.-]+\.internal\.example\.com`,
+		},
+	})
+	processor.Bootstrap(&types.ChangelogConfig{})
+
+	commit := processor.ProcessCommit(newPrivacyFixture())
+	assert.Equal("fix(host): connect to [REDACTED]", commit.Header)
+	assert.Equal("connect to [REDACTED]", commit.Subject)
+	assert.Equal("reported by [REDACTED]", commit.Body)
+	assert.Equal("connect to [REDACTED] by default", commit.ReleaseNote)
+	assert.Equal("see https://[REDACTED]/x", commit.Notes[0].Body)
+	assert.Equal("foo@example.com", commit.Author.Email)
+
+	for _, tt := range []struct {
+		policy        string
+		authorEmail   string
+		authorURL     string
+		coAuthorEmail string
+		coAuthorURL   string
+	}{
+		{types.EmailPolicyObfuscate, "foo [at] example [dot] com", "", "123+bar [at] users [dot] noreply [dot] github [dot] com", ""},
+		{types.EmailPolicyProfile, "", "https://github.com/foo-gh", "", "https://github.com/bar"},
+		{types.EmailPolicyOmit, "", "", "", ""},
+	} {
+		processor := NewPrivacyProcessor(&types.GlobalChangelogOutConfig{
+			EmailPolicy: tt.policy,
+			Profiles: map[string]string{
+				"FOO@example.com": "foo-gh",
+			},
+		})
+		processor.Bootstrap(&types.ChangelogConfig{})
+
+		commit := processor.ProcessCommit(newPrivacyFixture())
+		assert.Equal(tt.authorEmail, commit.Author.Email, tt.policy)
+		assert.Equal(tt.authorURL, commit.Author.URL, tt.policy)
+		assert.Equal(tt.authorEmail, commit.Committer.Email, tt.policy)
+		assert.Equal(tt.coAuthorEmail, commit.CoAuthors[0].Email, tt.policy)
+		assert.Equal(tt.coAuthorURL, commit.CoAuthors[0].URL, tt.policy)
+	}
+}
@@ -14,6 +14,12 @@ type Processor interface {
 	ProcessCommit(*types.Commit) *types.Commit
 }
 
+// IdentityProcessor is implemented by the processor hiding personal data, `Generator` processes
+// the contributors read from the identities of commits before processing by it
+type IdentityProcessor interface {
+	ProcessIdentity(*types.CommitContributor)
+}
+
 // GitHubProcessor is optimized for CHANGELOG used in GitHub
 //
 // The following processing is performed
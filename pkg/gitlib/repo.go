@@ -3,12 +3,15 @@ package gitlib
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"yunion.io/x/log"
 	"yunion.io/x/pkg/errors"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 )
 
 type Repository struct {
@@ -101,3 +104,69 @@ func (repo *Repository) Fetch() error {
 
 	return err
 }
+
+// FetchNotes fetches the notes ref of 'origin' remote, it's skipped if remote has no such ref
+func (repo *Repository) FetchNotes(ref string) error {
+	log.Infof("start fetch notes %q of %q", ref, repo.LogPrefix())
+
+	err := repo.Repository.Fetch(&git.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		Progress: os.Stdout,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	if _, ok := err.(git.NoMatchingRefSpecError); ok {
+		log.Warningf("notes %q not found in %q", ref, repo.LogPrefix())
+		return nil
+	}
+
+	return err
+}
+
+// CommitFiles adds files to index and commits them, the file path is relative to repository root.
+// Author is read from git config
+func (repo *Repository) CommitFiles(message string, files ...string) (plumbing.Hash, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "get worktree")
+	}
+
+	for _, file := range files {
+		if _, err := wt.Add(file); err != nil {
+			return plumbing.ZeroHash, errors.Wrapf(err, "add %q", file)
+		}
+	}
+
+	hash, err := wt.Commit(message, &git.CommitOptions{})
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "commit")
+	}
+
+	return hash, nil
+}
+
+// RemoteBranches returns the branch names of 'origin' remote
+func (repo *Repository) RemoteBranches() ([]string, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, errors.Wrap(err, "list references")
+	}
+	defer refs.Close()
+
+	prefix := "refs/remotes/origin/"
+	var branches []string
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		if !strings.HasPrefix(name, prefix) || ref.Type() == plumbing.SymbolicReference {
+			return nil
+		}
+		branches = append(branches, strings.TrimPrefix(name, prefix))
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "iterate references")
+	}
+	sort.Strings(branches)
+
+	return branches, nil
+}
@@ -15,6 +15,11 @@ import (
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
+const (
+	// tagRecordSeparator separates tags, the body of tag has multiple lines
+	tagRecordSeparator = "@@__CHGLOG_TAG__@@"
+)
+
 type TagReader interface {
 	ReadAll() ([]*types.Tag, error)
 }
@@ -25,6 +30,8 @@ type tagReader struct {
 	separator string
 	reFilter  *regexp.Regexp
 	useSemVer bool
+	// signatures caches the verified signature status of tag names, `ReadAll` may be called many times
+	signatures map[string]string
 }
 
 func NewTagReader(client gitcmd.Client, filterPattern string) *tagReader {
@@ -49,7 +56,17 @@ func (r *tagReader) ReadAll() ([]*types.Tag, error) {
 	out, err := r.client.Exec(
 		"for-each-ref",
 		"--format",
-		"%(refname)"+r.separator+"%(subject)"+r.separator+"%(taggerdate)"+r.separator+"%(authordate)",
+		tagRecordSeparator+strings.Join([]string{
+			"%(refname)",
+			"%(subject)",
+			"%(taggerdate)",
+			"%(authordate)",
+			"%(objecttype)",
+			"%(taggername)",
+			"%(taggeremail)",
+			"%(contents:body)",
+			"%(contents:signature)",
+		}, r.separator),
 		"refs/tags",
 	)
 
@@ -59,12 +76,12 @@ func (r *tagReader) ReadAll() ([]*types.Tag, error) {
 		return tags, fmt.Errorf("failed to get git-tag: %s", err.Error())
 	}
 
-	lines := strings.Split(out, "\n")
+	records := strings.Split(out, tagRecordSeparator)
 
-	for _, line := range lines {
-		tokens := strings.Split(line, r.separator)
+	for _, record := range records {
+		tokens := strings.Split(record, r.separator)
 
-		if len(tokens) != 4 {
+		if len(tokens) != 9 {
 			continue
 		}
 
@@ -97,12 +114,18 @@ func (r *tagReader) ReadAll() ([]*types.Tag, error) {
 			ver = &verObj
 		}
 
-		tags = append(tags, &types.Tag{
+		tag := &types.Tag{
 			Name:    name,
 			Subject: subject,
 			Date:    date,
 			Version: ver,
-		})
+		}
+		// body, tagger and signature are left empty for lightweight tags
+		if tokens[4] == "tag" {
+			r.parseAnnotation(tag, tokens[5], tokens[6], tokens[7], tokens[8])
+		}
+
+		tags = append(tags, tag)
 	}
 
 	r.sortTags(tags, r.useSemVer)
@@ -119,6 +142,33 @@ func (*tagReader) parseSubject(input string) string {
 	return strings.TrimSpace(input)
 }
 
+func (r *tagReader) parseAnnotation(tag *types.Tag, taggerName, taggerEmail, body, signature string) {
+	tag.Body = strings.TrimSpace(body)
+	if taggerName != "" || taggerEmail != "" {
+		tag.Tagger = &types.CommitContributor{
+			Name:  strings.TrimSpace(taggerName),
+			Email: strings.Trim(strings.TrimSpace(taggerEmail), "<>"),
+		}
+	}
+
+	tag.Signature = types.TagSignatureUnsigned
+	if strings.TrimSpace(signature) == "" {
+		return
+	}
+	if status, ok := r.signatures[tag.Name]; ok {
+		tag.Signature = status
+		return
+	}
+	tag.Signature = types.TagSignatureGood
+	if _, err := r.client.Exec("verify-tag", tag.Name); err != nil {
+		tag.Signature = types.TagSignatureUnverified
+	}
+	if r.signatures == nil {
+		r.signatures = make(map[string]string)
+	}
+	r.signatures[tag.Name] = tag.Signature
+}
+
 func (*tagReader) parseDate(input string) (time.Time, error) {
 	return time.ParseInLocation("Mon Jan 2 15:04:05 2006 -0700", input, time.UTC)
 }
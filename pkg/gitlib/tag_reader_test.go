@@ -13,20 +13,25 @@ import (
 
 func TestTagReader(t *testing.T) {
 	assert := assert.New(t)
+	verifies := 0
 	client := &mockClient{
 		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			if subcmd == "verify-tag" {
+				verifies++
+				return "", errors.New("no public key")
+			}
 			if subcmd != "for-each-ref" {
 				return "", errors.New("")
 			}
 			return strings.Join([]string{
 				"",
-				"refs/tags/v2.0.4-beta.1@@__CHGLOG__@@Release v2.0.4-beta.1@@__CHGLOG__@@Thu Feb 1 00:00:00 2018 +0000@@__CHGLOG__@@",
-				"refs/tags/4.4.3@@__CHGLOG__@@This is tag subject@@__CHGLOG__@@@@__CHGLOG__@@Fri Feb 2 00:00:00 2018 +0000",
-				"refs/tags/4.4.4@@__CHGLOG__@@Release 4.4.4@@__CHGLOG__@@Fri Feb 2 10:00:40 2018 +0000@@__CHGLOG__@@",
-				"refs/tags/5.0.0-rc.0@@__CHGLOG__@@Release 5.0.0-rc.0@@__CHGLOG__@@Sat Feb 3 12:30:10 2018 +0000@@__CHGLOG__@@",
-				"refs/tags/hoge_fuga@@__CHGLOG__@@Invalid semver tag name@@__CHGLOG__@@Mon Mar 12 12:30:10 2018 +0000@@__CHGLOG__@@",
-				"hoge@@__CHGLOG__@@",
-			}, "\n"), nil
+				"refs/tags/v2.0.4-beta.1@@__CHGLOG__@@Release v2.0.4-beta.1@@__CHGLOG__@@Thu Feb 1 00:00:00 2018 +0000@@__CHGLOG__@@@@__CHGLOG__@@commit@@__CHGLOG__@@@@__CHGLOG__@@@@__CHGLOG__@@@@__CHGLOG__@@\n",
+				"refs/tags/4.4.3@@__CHGLOG__@@This is tag subject@@__CHGLOG__@@@@__CHGLOG__@@Fri Feb 2 00:00:00 2018 +0000@@__CHGLOG__@@commit@@__CHGLOG__@@@@__CHGLOG__@@@@__CHGLOG__@@commit body@@__CHGLOG__@@\n",
+				"refs/tags/4.4.4@@__CHGLOG__@@Release 4.4.4@@__CHGLOG__@@Fri Feb 2 10:00:40 2018 +0000@@__CHGLOG__@@@@__CHGLOG__@@tag@@__CHGLOG__@@foo@@__CHGLOG__@@<foo@example.com>@@__CHGLOG__@@- highlight 1\n- highlight 2\n@@__CHGLOG__@@\n",
+				"refs/tags/5.0.0-rc.0@@__CHGLOG__@@Release 5.0.0-rc.0@@__CHGLOG__@@Sat Feb 3 12:30:10 2018 +0000@@__CHGLOG__@@@@__CHGLOG__@@tag@@__CHGLOG__@@bar@@__CHGLOG__@@<bar@example.com>@@__CHGLOG__@@@@__CHGLOG__@@-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n\n",
+				"refs/tags/hoge_fuga@@__CHGLOG__@@Invalid semver tag name@@__CHGLOG__@@Mon Mar 12 12:30:10 2018 +0000@@__CHGLOG__@@@@__CHGLOG__@@commit@@__CHGLOG__@@@@__CHGLOG__@@@@__CHGLOG__@@@@__CHGLOG__@@\n",
+				"hoge@@__CHGLOG__@@\n",
+			}, tagRecordSeparator), nil
 		},
 	}
 
@@ -47,9 +52,11 @@ func TestTagReader(t *testing.T) {
 				},
 			},
 			{
-				Name:    "5.0.0-rc.0",
-				Subject: "Release 5.0.0-rc.0",
-				Date:    time.Date(2018, 2, 3, 12, 30, 10, 0, time.UTC),
+				Name:      "5.0.0-rc.0",
+				Subject:   "Release 5.0.0-rc.0",
+				Date:      time.Date(2018, 2, 3, 12, 30, 10, 0, time.UTC),
+				Tagger:    &types.CommitContributor{Name: "bar", Email: "bar@example.com"},
+				Signature: types.TagSignatureUnverified,
 				Next: &types.RelateTag{
 					Name:    "hoge_fuga",
 					Subject: "Invalid semver tag name",
@@ -62,9 +69,12 @@ func TestTagReader(t *testing.T) {
 				},
 			},
 			{
-				Name:    "4.4.4",
-				Subject: "Release 4.4.4",
-				Date:    time.Date(2018, 2, 2, 10, 0, 40, 0, time.UTC),
+				Name:      "4.4.4",
+				Subject:   "Release 4.4.4",
+				Date:      time.Date(2018, 2, 2, 10, 0, 40, 0, time.UTC),
+				Body:      "- highlight 1\n- highlight 2",
+				Tagger:    &types.CommitContributor{Name: "foo", Email: "foo@example.com"},
+				Signature: types.TagSignatureUnsigned,
 				Next: &types.RelateTag{
 					Name:    "5.0.0-rc.0",
 					Subject: "Release 5.0.0-rc.0",
@@ -106,6 +116,15 @@ func TestTagReader(t *testing.T) {
 		actual,
 	)
 
+	// the signature of tag is verified once by the same reader
+	verifies = 0
+	reader := NewTagReader(client, "")
+	for i := 0; i < 2; i++ {
+		_, err := reader.ReadAll()
+		assert.Nil(err)
+	}
+	assert.Equal(1, verifies)
+
 	actual_filtered, err_filtered := NewTagReader(client, "^v").ReadAll()
 	assert.Nil(err_filtered)
 	assert.Equal(
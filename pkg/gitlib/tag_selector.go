@@ -2,6 +2,9 @@ package gitlib
 
 import (
 	"strings"
+	"time"
+
+	gitcmd "github.com/tsuyoshiwada/go-gitcmd"
 
 	"yunion.io/x/pkg/errors"
 
@@ -11,27 +14,65 @@ import (
 const (
 	ErrNotFoundTag      = errors.Error("could not find the tag")
 	ErrFailedQueryParse = errors.Error("failed to parse the query")
+
+	// date endpoints of query, e.g. `since:2024-01-01..until:2024-06-30`
+	sinceQueryPrefix = "since:"
+	untilQueryPrefix = "until:"
+)
+
+var (
+	queryDateLayouts = []string{
+		"2006-01-02",
+		time.RFC3339,
+	}
 )
 
 type TagSelector interface {
 	Select(tags []*types.Tag, query string) ([]*types.Tag, string, error)
 }
 
-type tagSelector struct{}
+// RefResolver resolves the commit and branch endpoints of query
+type RefResolver interface {
+	// ResolveCommit returns the commit hash of rev
+	ResolveCommit(rev string) (string, error)
+	// IsAncestor reports whether ancestor is reachable from rev
+	IsAncestor(ancestor string, rev string) bool
+}
 
+type tagSelector struct {
+	resolver RefResolver
+}
+
+// NewTagSelector selects tags by tag names and dates
 func NewTagSelector() TagSelector {
 	return &tagSelector{}
 }
 
+// NewGitTagSelector selects tags by tag names, dates, commits and branches
+func NewGitTagSelector(client gitcmd.Client) TagSelector {
+	return &tagSelector{
+		resolver: &gitRefResolver{client: client},
+	}
+}
+
 func (s *tagSelector) Select(tags []*types.Tag, query string) ([]*types.Tag, string, error) {
 	tokens := strings.Split(query, "..")
 
 	switch len(tokens) {
 	case 1:
-		return s.selectSingleTag(tags, tokens[0])
+		if hasTag(tags, tokens[0]) {
+			return s.selectSingleTag(tags, tokens[0])
+		}
+		if strings.HasPrefix(tokens[0], sinceQueryPrefix) {
+			return s.selectExtendedTags(tags, tokens[0], "")
+		}
+		return s.selectExtendedTags(tags, "", tokens[0])
 	case 2:
 		old := tokens[0]
 		new := tokens[1]
+		if (old != "" && !hasTag(tags, old)) || (new != "" && !hasTag(tags, new)) {
+			return s.selectExtendedTags(tags, old, new)
+		}
 		if old == "" && new == "" {
 			return nil, "", nil
 		} else if old == "" {
@@ -45,6 +86,19 @@ func (s *tagSelector) Select(tags []*types.Tag, query string) ([]*types.Tag, str
 	return nil, "", ErrFailedQueryParse
 }
 
+func hasTag(tags []*types.Tag, name string) bool {
+	return tagIndex(tags, name) >= 0
+}
+
+func tagIndex(tags []*types.Tag, name string) int {
+	for i, tag := range tags {
+		if tag.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (s *tagSelector) selectSingleTag(tags []*types.Tag, token string) ([]*types.Tag, string, error) {
 	var from string
 
@@ -57,7 +111,7 @@ func (s *tagSelector) selectSingleTag(tags []*types.Tag, token string) ([]*types
 		}
 	}
 
-	return nil, "", nil
+	return nil, "", errors.Wrapf(ErrNotFoundTag, "selectSingleTag token: %s", token)
 }
 
 func (*tagSelector) selectBeforeTags(tags []*types.Tag, token string) ([]*types.Tag, string, error) {
@@ -94,6 +148,10 @@ func (*tagSelector) selectAfterTags(tags []*types.Tag, token string) ([]*types.T
 		from string
 	)
 
+	if !hasTag(tags, token) {
+		return nil, "", errors.Wrapf(ErrNotFoundTag, "selectAfterTags token: %s", token)
+	}
+
 	for i, tag := range tags {
 		res = append(res, tag)
 		from = ""
@@ -106,10 +164,6 @@ func (*tagSelector) selectAfterTags(tags []*types.Tag, token string) ([]*types.T
 		}
 	}
 
-	if len(res) == 0 {
-		return res, "", errors.Wrapf(ErrNotFoundTag, "selectAfterTags token: %s", token)
-	}
-
 	return res, from, nil
 }
 
@@ -144,3 +198,185 @@ func (s *tagSelector) selectRangeTags(tags []*types.Tag, old string, new string)
 
 	return res, from, nil
 }
+
+// selectExtendedTags selects tags by endpoints of tag name, date, commit or branch.
+//
+//	old endpoint - tags newer than it, the tag itself is included
+//	  <tagname>         - the tag is included
+//	  since:<date>      - tags created at or after the date
+//	  <commit|branch>   - tags not reachable from the commit, which is returned as `from`
+//	new endpoint - tags older than it
+//	  <tagname>         - the tag is included
+//	  until:<date>      - tags created at or before the date
+//	  <commit|branch>   - tags reachable from the commit
+func (s *tagSelector) selectExtendedTags(tags []*types.Tag, old string, new string) ([]*types.Tag, string, error) {
+	oldMatch, oldFrom, err := s.oldEndpointMatcher(tags, old)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "old: %q", old)
+	}
+	newMatch, err := s.newEndpointMatcher(tags, new)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "new: %q", new)
+	}
+
+	var (
+		res  []*types.Tag
+		from string
+	)
+	for i, tag := range tags {
+		if !oldMatch(i, tag) || !newMatch(i, tag) {
+			continue
+		}
+		res = append(res, tag)
+		from = ""
+		if i+1 < len(tags) {
+			from = tags[i+1].Name
+		}
+	}
+
+	if len(res) == 0 {
+		return res, "", errors.Wrapf(ErrNotFoundTag, "no tags between old: %q, new: %q", old, new)
+	}
+
+	if oldFrom != nil {
+		from = oldFrom(res)
+	}
+
+	return res, from, nil
+}
+
+type tagMatcher func(idx int, tag *types.Tag) bool
+
+func matchAllTags(int, *types.Tag) bool {
+	return true
+}
+
+// oldEndpointMatcher returns the matcher of endpoint, and the function to compute `from` of the selected tags if needed
+func (s *tagSelector) oldEndpointMatcher(tags []*types.Tag, endpoint string) (tagMatcher, func([]*types.Tag) string, error) {
+	if endpoint == "" {
+		return matchAllTags, nil, nil
+	}
+
+	if idx := tagIndex(tags, endpoint); idx >= 0 {
+		return func(i int, _ *types.Tag) bool {
+			return i <= idx
+		}, nil, nil
+	}
+
+	if strings.HasPrefix(endpoint, untilQueryPrefix) {
+		return nil, nil, errors.Wrapf(ErrFailedQueryParse, "%q can only be used as new endpoint", untilQueryPrefix)
+	}
+
+	if strings.HasPrefix(endpoint, sinceQueryPrefix) {
+		since, err := parseQueryDate(strings.TrimPrefix(endpoint, sinceQueryPrefix))
+		if err != nil {
+			return nil, nil, err
+		}
+		match := func(_ int, tag *types.Tag) bool {
+			return !tag.Date.Before(since)
+		}
+		// the newest tag before the date
+		from := func([]*types.Tag) string {
+			var prev *types.Tag
+			for _, tag := range tags {
+				if !match(0, tag) && (prev == nil || tag.Date.After(prev.Date)) {
+					prev = tag
+				}
+			}
+			if prev == nil {
+				return ""
+			}
+			return prev.Name
+		}
+		return match, from, nil
+	}
+
+	hash, err := s.resolveCommit(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(_ int, tag *types.Tag) bool {
+			return !s.resolver.IsAncestor(tag.Name, hash)
+		}, func([]*types.Tag) string {
+			return hash
+		}, nil
+}
+
+func (s *tagSelector) newEndpointMatcher(tags []*types.Tag, endpoint string) (tagMatcher, error) {
+	if endpoint == "" {
+		return matchAllTags, nil
+	}
+
+	if idx := tagIndex(tags, endpoint); idx >= 0 {
+		return func(i int, _ *types.Tag) bool {
+			return i >= idx
+		}, nil
+	}
+
+	if strings.HasPrefix(endpoint, sinceQueryPrefix) {
+		return nil, errors.Wrapf(ErrFailedQueryParse, "%q can only be used as old endpoint", sinceQueryPrefix)
+	}
+
+	if strings.HasPrefix(endpoint, untilQueryPrefix) {
+		until, err := parseQueryDate(strings.TrimPrefix(endpoint, untilQueryPrefix))
+		if err != nil {
+			return nil, err
+		}
+		// the whole day is included
+		if len(strings.TrimPrefix(endpoint, untilQueryPrefix)) == len(queryDateLayouts[0]) {
+			until = until.Add(24*time.Hour - time.Nanosecond)
+		}
+		return func(_ int, tag *types.Tag) bool {
+			return !tag.Date.After(until)
+		}, nil
+	}
+
+	hash, err := s.resolveCommit(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ int, tag *types.Tag) bool {
+		return s.resolver.IsAncestor(tag.Name, hash)
+	}, nil
+}
+
+// resolveCommit resolves commit or branch, the remote tracking branch of 'origin' is also tried
+func (s *tagSelector) resolveCommit(rev string) (string, error) {
+	if s.resolver == nil {
+		return "", errors.Wrapf(ErrNotFoundTag, "%s", rev)
+	}
+
+	for _, ref := range []string{rev, "origin/" + rev} {
+		if hash, err := s.resolver.ResolveCommit(ref); err == nil {
+			return hash, nil
+		}
+	}
+
+	return "", errors.Wrapf(ErrNotFoundTag, "%s is not a tag, commit or branch", rev)
+}
+
+func parseQueryDate(input string) (time.Time, error) {
+	for _, layout := range queryDateLayouts {
+		if t, err := time.ParseInLocation(layout, input, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Wrapf(ErrFailedQueryParse, "invalid date %q", input)
+}
+
+type gitRefResolver struct {
+	client gitcmd.Client
+}
+
+func (r *gitRefResolver) ResolveCommit(rev string) (string, error) {
+	out, err := r.client.Exec("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (r *gitRefResolver) IsAncestor(ancestor string, rev string) bool {
+	_, err := r.client.Exec("merge-base", "--is-ancestor", ancestor, rev)
+	return err == nil
+}
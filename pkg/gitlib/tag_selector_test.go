@@ -2,9 +2,12 @@ package gitlib
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"yunion.io/x/pkg/errors"
+
 	"github.com/yunionio/git-tools/pkg/types"
 )
 
@@ -101,3 +104,141 @@ func TestTagSelector(t *testing.T) {
 		assert.Equal(expected[len(expected)-1], from)
 	}
 }
+
+func TestTagSelectorExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	fixtures := []*types.Tag{
+		{Name: "v1.3.0", Date: date("2024-07-10")},
+		{Name: "v1.2.1", Date: date("2024-06-30")},
+		{Name: "v1.2.0", Date: date("2024-03-01")},
+		{Name: "v1.1.0", Date: date("2023-12-20")},
+		{Name: "v1.0.0", Date: date("2023-06-01")},
+	}
+
+	// the branch `release/1.2` forks after v1.1.0, `abc123` is the commit between v1.2.0 and v1.2.1
+	reachable := map[string][]string{
+		"release-1.2-hash": {"v1.2.1", "v1.2.0", "v1.1.0", "v1.0.0"},
+		"abc123-hash":      {"v1.2.0", "v1.1.0", "v1.0.0"},
+	}
+	client := &mockClient{
+		ReturnExec: func(subcmd string, args ...string) (string, error) {
+			switch subcmd {
+			case "rev-parse":
+				switch args[len(args)-1] {
+				case "origin/release/1.2^{commit}":
+					return "release-1.2-hash\n", nil
+				case "abc123^{commit}":
+					return "abc123-hash\n", nil
+				}
+			case "merge-base":
+				for _, name := range reachable[args[2]] {
+					if name == args[1] {
+						return "", nil
+					}
+				}
+			}
+			return "", errors.Error("exit status 1")
+		},
+	}
+	selector := NewGitTagSelector(client)
+
+	table := map[string][]string{
+		"since:2024-01-01..until:2024-06-30": {
+			"v1.2.1",
+			"v1.2.0",
+			"v1.1.0",
+		},
+		"since:2024-03-01..": {
+			"v1.3.0",
+			"v1.2.1",
+			"v1.2.0",
+			"v1.1.0",
+		},
+		"since:2024-03-02": {
+			"v1.3.0",
+			"v1.2.1",
+			"v1.2.0",
+		},
+		"..until:2023-12-31": {
+			"v1.1.0",
+			"v1.0.0",
+			"",
+		},
+		"v1.1.0..until:2024-06-30": {
+			"v1.2.1",
+			"v1.2.0",
+			"v1.1.0",
+			"v1.0.0",
+		},
+		"abc123..": {
+			"v1.3.0",
+			"v1.2.1",
+			"abc123-hash",
+		},
+		"..release/1.2": {
+			"v1.2.1",
+			"v1.2.0",
+			"v1.1.0",
+			"v1.0.0",
+			"",
+		},
+		"release/1.2": {
+			"v1.2.1",
+			"v1.2.0",
+			"v1.1.0",
+			"v1.0.0",
+			"",
+		},
+		"abc123..release/1.2": {
+			"v1.2.1",
+			"abc123-hash",
+		},
+	}
+
+	for query, expected := range table {
+		list, from, err := selector.Select(fixtures, query)
+		actual := make([]string, len(list))
+		for i, tag := range list {
+			actual[i] = tag.Name
+		}
+
+		assert.Nil(err, query)
+		assert.Equal(expected[0:len(expected)-1], actual, query)
+		assert.Equal(expected[len(expected)-1], from, query)
+	}
+
+	notFound := []string{
+		"v9.9.9",
+		"v9.9.9..",
+		"..v9.9.9",
+		"v1.0.0..v9.9.9",
+		"unknown..release/1.2",
+		"since:2025-01-01..",
+		"..until:2020-01-01",
+	}
+	for _, query := range notFound {
+		_, _, err := selector.Select(fixtures, query)
+		assert.Equal(ErrNotFoundTag, errors.Cause(err), query)
+	}
+
+	invalid := []string{
+		"until:2024-01-01..",
+		"..since:2024-01-01",
+		"since:yesterday..",
+		"v1.0.0..v1.1.0..v1.2.0",
+	}
+	for _, query := range invalid {
+		_, _, err := selector.Select(fixtures, query)
+		assert.Equal(ErrFailedQueryParse, errors.Cause(err), query)
+	}
+
+	// commits and branches are unresolved without git
+	_, _, err := NewTagSelector().Select(fixtures, "..release/1.2")
+	assert.Equal(ErrNotFoundTag, errors.Cause(err))
+}
@@ -0,0 +1,64 @@
+package types
+
+const (
+	// TagIssueMissing means the version is tagged by other repos, but not by this repo
+	TagIssueMissing = "missing"
+	// TagIssueWrongBranch means the tag is not reachable from the release branch but other branches
+	TagIssueWrongBranch = "wrong-branch"
+	// TagIssueUnreachable means the tag is not reachable from any branch
+	TagIssueUnreachable = "unreachable"
+	// TagIssueSkew means the latest version of this repo is behind the release
+	TagIssueSkew = "skew"
+)
+
+type GlobalTagAudit struct {
+	Releases []*ReleaseTagAudit `json:"releases"`
+}
+
+// HasIssues reports whether any release has issues
+func (a *GlobalTagAudit) HasIssues() bool {
+	for _, rls := range a.Releases {
+		if len(rls.Issues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseTagAudit is the matrix of versions against repos of release branch
+type ReleaseTagAudit struct {
+	Branch string `json:"branch"`
+	// Versions tagged by any repo, sorted from the newest
+	Versions []string        `json:"versions"`
+	Repos    []*RepoTagAudit `json:"repos"`
+	Issues   []*TagIssue     `json:"issues"`
+}
+
+type RepoTagAudit struct {
+	Repo string      `json:"repo"`
+	Tags []*TagAudit `json:"tags"`
+}
+
+// Tag returns the tag of version, `nil` if the version is not tagged
+func (r *RepoTagAudit) Tag(version string) *TagAudit {
+	for _, tag := range r.Tags {
+		if tag.Version == version {
+			return tag
+		}
+	}
+	return nil
+}
+
+type TagAudit struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Branches containing the tag, remote branches are prefixed by remote name (e.g. `origin/release/3.6`)
+	Branches []string `json:"branches"`
+}
+
+type TagIssue struct {
+	Repo    string `json:"repo"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
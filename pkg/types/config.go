@@ -68,6 +68,14 @@ type GlobalChangeLogConfigV1 struct {
 	Releases []*ReleaseChangeLogConfigV1 `json:"releases"`
 	// Output configure output handle options
 	Output *GlobalChangelogOutConfig `json:"output"`
+	// Discovery finds release branches of repos instead of listing them in Releases
+	Discovery *ReleaseDiscoveryConfigV1 `json:"discovery"`
+	// RepoFilters are commit filters of each repo name, e.g. `onecloud`
+	RepoFilters map[string]*CommitFilterConfig `json:"repoFilters"`
+	// RepoPaths are path specs of each repo name, the commits are restricted to the paths, e.g. `pkg/compute`
+	RepoPaths map[string][]string `json:"repoPaths"`
+	// RepoComponents map path globs to components of each repo name
+	RepoComponents map[string][]*ComponentConfig `json:"repoComponents"`
 }
 
 func (c *GlobalChangeLogConfigV1) ToInternalConfig() (*GlobalChangeLogConfig, error) {
@@ -75,12 +83,36 @@ func (c *GlobalChangeLogConfigV1) ToInternalConfig() (*GlobalChangeLogConfig, er
 		return nil, errors.Errorf("cacheDir must specified")
 	}
 
+	if c.Output != nil {
+		if err := c.Output.Validate(); err != nil {
+			return nil, errors.Wrap(err, "output config")
+		}
+	}
+
+	if c.Options != nil {
+		if err := c.Options.Validate(); err != nil {
+			return nil, errors.Wrap(err, "options config")
+		}
+	}
+
 	ic := &GlobalChangeLogConfig{
 		Bin:      "git",
 		CacheDir: c.CacheDir,
 		Template: c.Template,
 		Options:  c.Options,
 		Output:   c.Output,
+
+		RepoFilters:    c.RepoFilters,
+		RepoPaths:      c.RepoPaths,
+		RepoComponents: c.RepoComponents,
+	}
+
+	for repo, components := range c.RepoComponents {
+		for _, component := range components {
+			if component.Name == "" || len(component.Paths) == 0 {
+				return nil, errors.Errorf("component of repo %q must have name and paths", repo)
+			}
+		}
 	}
 
 	for _, rls := range c.Releases {
@@ -92,20 +124,69 @@ func (c *GlobalChangeLogConfigV1) ToInternalConfig() (*GlobalChangeLogConfig, er
 		ic.Releases = append(ic.Releases, iRls)
 	}
 
+	if c.Discovery != nil {
+		discovery, err := c.Discovery.ToInternalConfig(c.CacheDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "discovery config")
+		}
+		ic.Discovery = discovery
+	}
+
 	return ic, nil
 }
 
 type ReleaseChangeLogConfigV1 struct {
 	Branch string   `json:"branch"`
 	Repos  []string `json:"repos"`
+	// BaseRef is the start of the first version of release branch, e.g. `v3.5.0` or `master`,
+	// default is the fork point with the previous release line
+	BaseRef string `json:"baseRef"`
 }
 
 func (c *ReleaseChangeLogConfigV1) ToInternalConfig(cacheDir string) (*ReleaseChangeLogConfig, error) {
+	repos, err := newRepositories(cacheDir, c.Repos)
+	if err != nil {
+		return nil, err
+	}
+
 	ic := &ReleaseChangeLogConfig{
-		Branch: c.Branch,
+		Branch:  c.Branch,
+		Repos:   repos,
+		BaseRef: c.BaseRef,
+	}
+
+	return ic, nil
+}
+
+type ReleaseDiscoveryConfigV1 struct {
+	Repos []string `json:"repos"`
+	// Latest is the count of newest release lines to generate, all are used if it's 0
+	Latest int `json:"latest"`
+	// MinVersion is the oldest `major.minor` release line to generate, e.g. `3.4`
+	MinVersion string `json:"minVersion"`
+}
+
+func (c *ReleaseDiscoveryConfigV1) ToInternalConfig(cacheDir string) (*ReleaseDiscoveryConfig, error) {
+	if c.Latest < 0 {
+		return nil, errors.Errorf("latest %d must not be negative", c.Latest)
+	}
+
+	repos, err := newRepositories(cacheDir, c.Repos)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, url := range c.Repos {
+	return &ReleaseDiscoveryConfig{
+		Repos:      repos,
+		Latest:     c.Latest,
+		MinVersion: c.MinVersion,
+	}, nil
+}
+
+func newRepositories(cacheDir string, urls []string) ([]*Repository, error) {
+	var repos []*Repository
+
+	for _, url := range urls {
 		repo := new(Repository)
 		repo.URL = strings.TrimRight(url, "/")
 		urlSegs := strings.Split(url, "/")
@@ -114,8 +195,8 @@ func (c *ReleaseChangeLogConfigV1) ToInternalConfig(cacheDir string) (*ReleaseCh
 		}
 		repo.Name = urlSegs[len(urlSegs)-1]
 		repo.WorkingDir = path.Join(cacheDir, repo.Name)
-		ic.Repos = append(ic.Repos, repo)
+		repos = append(repos, repo)
 	}
 
-	return ic, nil
+	return repos, nil
 }
@@ -0,0 +1,47 @@
+package types
+
+const (
+	BumpNone  = "none"
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+// BumpLevelWeight returns the order of bump level, unknown level is treated as `none`
+func BumpLevelWeight(level string) int {
+	switch level {
+	case BumpPatch:
+		return 1
+	case BumpMinor:
+		return 2
+	case BumpMajor:
+		return 3
+	}
+	return 0
+}
+
+type GlobalNextVersionResult struct {
+	Releases []*ReleaseNextVersion `json:"releases"`
+}
+
+type ReleaseNextVersion struct {
+	Branch string `json:"branch"`
+	// Next bumps the newest released version of repos by the highest bump level,
+	// it's empty if no repo has unreleased commits
+	Next  string             `json:"next"`
+	Repos []*RepoNextVersion `json:"repos"`
+	// Failures of repos skipped by `KeepGoing`
+	Failures []*RepoFailure `json:"failures"`
+}
+
+type RepoNextVersion struct {
+	Repo string `json:"repo"`
+	// Current is the latest tag of release branch
+	Current string `json:"current"`
+	// Next is empty if there are no unreleased commits, i.e. `Bump` is `none`
+	Next string `json:"next"`
+	// Bump is the level computed from unreleased commits
+	Bump            string `json:"bump"`
+	Commits         int    `json:"commits"`
+	BreakingChanges int    `json:"breakingChanges"`
+}
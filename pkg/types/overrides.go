@@ -0,0 +1,174 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"yunion.io/x/pkg/errors"
+)
+
+var (
+	reOverrideCommit = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+)
+
+// CommitOverrides rewrites or hides commits in CHANGELOG, the yaml file is
+//
+//	overrides:
+//	- commit: 1a2b3c4
+//	  hide: true
+//	- changeId: I8473b95934b5732ac55d26311a706c9c2bde9940
+//	  type: fix
+//	  scope: host
+//	  subject: fix typo of subject
+//	  releaseNote: Fix the typo of host subject
+type CommitOverrides struct {
+	Overrides []*CommitOverride `json:"overrides"`
+	// used overrides by `Match`
+	used map[*CommitOverride]bool
+}
+
+// CommitOverride matches commit by hash (long or short) or `Change-Id` trailer,
+// empty fields keep the ones of commit. The release note of commit is dropped if only Subject is overridden
+type CommitOverride struct {
+	Commit   string `json:"commit"`
+	ChangeID string `json:"changeId"`
+	// Hide drops the commit from CHANGELOG
+	Hide    bool   `json:"hide"`
+	Type    string `json:"type"`
+	Scope   string `json:"scope"`
+	Subject string `json:"subject"`
+	// ReleaseNote replaces the release note of commit
+	ReleaseNote string        `json:"releaseNote"`
+	Notes       []*CommitNote `json:"notes"`
+}
+
+func (o *CommitOverride) String() string {
+	if o.Commit != "" {
+		return "commit " + o.Commit
+	}
+	return "changeId " + o.ChangeID
+}
+
+func (os *CommitOverrides) Validate() error {
+	for idx, o := range os.Overrides {
+		if o.Commit == "" && o.ChangeID == "" {
+			return errors.Errorf("override %d: commit or changeId is required", idx)
+		}
+		if o.Commit != "" && !reOverrideCommit.MatchString(o.Commit) {
+			return errors.Errorf("override %d: commit %q must be a hash of at least 7 characters", idx, o.Commit)
+		}
+	}
+	return nil
+}
+
+// Match returns the first override matching the commit, the override is marked as used
+func (os *CommitOverrides) Match(commit *Commit) *CommitOverride {
+	if os == nil {
+		return nil
+	}
+
+	for _, o := range os.Overrides {
+		if o.matchCommit(commit) {
+			if os.used == nil {
+				os.used = make(map[*CommitOverride]bool)
+			}
+			os.used[o] = true
+			return o
+		}
+	}
+	return nil
+}
+
+// Unused returns the overrides not matched by any commit
+func (os *CommitOverrides) Unused() []*CommitOverride {
+	ret := make([]*CommitOverride, 0)
+	for _, o := range os.Overrides {
+		if !os.used[o] {
+			ret = append(ret, o)
+		}
+	}
+	return ret
+}
+
+func (o *CommitOverride) matchCommit(commit *Commit) bool {
+	if o.Commit != "" && commit.Hash != nil {
+		return strings.HasPrefix(strings.ToLower(commit.Hash.Long), strings.ToLower(o.Commit))
+	}
+	if o.ChangeID != "" {
+		return o.ChangeID == commit.ChangeID
+	}
+	return false
+}
+
+// Apply rewrites the commit, `nil` is returned if the commit is hidden
+func (o *CommitOverride) Apply(commit *Commit) *Commit {
+	if o.Hide {
+		return nil
+	}
+
+	if o.Type == "" && o.Scope == "" && o.Subject == "" && o.ReleaseNote == "" && o.Notes == nil {
+		return commit
+	}
+
+	if o.Type != "" {
+		commit.Type = o.Type
+	}
+	if o.Scope != "" {
+		commit.Scope = o.Scope
+	}
+	if o.Subject != "" {
+		commit.Subject = o.Subject
+		// release note is preferred in CHANGELOG, drop the stale one of commit body
+		commit.ReleaseNote = ""
+	}
+	if o.ReleaseNote != "" {
+		commit.ReleaseNote = o.ReleaseNote
+	}
+	if o.Notes != nil {
+		commit.Notes = o.Notes
+	}
+
+	if o.Type != "" || o.Scope != "" || o.Subject != "" {
+		commit.Header = commit.Subject
+		if commit.Type != "" {
+			scope := ""
+			if commit.Scope != "" {
+				scope = fmt.Sprintf("(%s)", commit.Scope)
+			}
+			commit.Header = fmt.Sprintf("%s%s: %s", commit.Type, scope, commit.Subject)
+		}
+	}
+
+	return commit
+}
+
+// CommitNoteErrors collects the malformed git notes of commits found by parsers
+type CommitNoteErrors struct {
+	errs map[string]string
+}
+
+// Add records the error of note, the same commit of a repository is recorded once
+func (e *CommitNoteErrors) Add(repo string, commit string, err error) {
+	if e == nil {
+		return
+	}
+	if e.errs == nil {
+		e.errs = make(map[string]string)
+	}
+	e.errs[fmt.Sprintf("%s: commit %s", repo, commit)] = err.Error()
+}
+
+// Errors returns the sorted messages of malformed notes
+func (e *CommitNoteErrors) Errors() []string {
+	ret := make([]string, 0)
+	if e == nil {
+		return ret
+	}
+	for key, msg := range e.errs {
+		ret = append(ret, fmt.Sprintf("%s: %s", key, msg))
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"time"
+)
+
+// Stats is the metrics of a set of commits
+type Stats struct {
+	Commits int `json:"commits"`
+	// Commits count by `Type`, commits without type are counted as `Others`
+	Types map[string]int `json:"types"`
+	// Commits count by `Scope`, commits without scope are not counted
+	Scopes map[string]int `json:"scopes"`
+	// Commits count having breaking change notes
+	BreakingChanges int `json:"breakingChanges"`
+	Reverts         int `json:"reverts"`
+	// Distinct contributors count
+	Contributors          int `json:"contributors"`
+	FirstTimeContributors int `json:"firstTimeContributors"`
+
+	contributors map[string]bool
+	firstTimes   map[string]bool
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		Types:        make(map[string]int),
+		Scopes:       make(map[string]int),
+		contributors: make(map[string]bool),
+		firstTimes:   make(map[string]bool),
+	}
+}
+
+// AddContributor counts the contributor only once
+func (s *Stats) AddContributor(c *Contributor) {
+	if !s.contributors[c.Key()] {
+		s.contributors[c.Key()] = true
+		s.Contributors++
+	}
+	if c.FirstTime && !s.firstTimes[c.Key()] {
+		s.firstTimes[c.Key()] = true
+		s.FirstTimeContributors++
+	}
+}
+
+// Merge sums up other stats into s, contributors are deduplicated
+func (s *Stats) Merge(other *Stats) {
+	s.Commits += other.Commits
+	for k, v := range other.Types {
+		s.Types[k] += v
+	}
+	for k, v := range other.Scopes {
+		s.Scopes[k] += v
+	}
+	s.BreakingChanges += other.BreakingChanges
+	s.Reverts += other.Reverts
+
+	for key := range other.contributors {
+		if !s.contributors[key] {
+			s.contributors[key] = true
+			s.Contributors++
+		}
+	}
+	for key := range other.firstTimes {
+		if !s.firstTimes[key] {
+			s.firstTimes[key] = true
+			s.FirstTimeContributors++
+		}
+	}
+}
+
+type GlobalStats struct {
+	Releases []*ReleaseStats `json:"releases"`
+}
+
+type ReleaseStats struct {
+	Branch string `json:"branch"`
+	*Stats
+	Versions []*VersionStats `json:"versions"`
+}
+
+type VersionStats struct {
+	TagName string    `json:"tagName"`
+	Date    time.Time `json:"date"`
+	// PreviousTagName is the previous version of the release branch
+	PreviousTagName   string `json:"previousTagName"`
+	DaysSincePrevious int    `json:"daysSincePrevious"`
+	*Stats
+	Repos []*RepoVersionStats `json:"repos"`
+}
+
+type RepoVersionStats struct {
+	Repo              string    `json:"repo"`
+	TagName           string    `json:"tagName"`
+	Date              time.Time `json:"date"`
+	PreviousTagName   string    `json:"previousTagName"`
+	DaysSincePrevious int       `json:"daysSincePrevious"`
+	*Stats
+}
+
+// DaysBetween returns whole days from previous to date, 0 if any of them is zero
+func DaysBetween(previous, date time.Time) int {
+	if previous.IsZero() || date.IsZero() {
+		return 0
+	}
+	return int(date.Sub(previous).Hours() / 24)
+}
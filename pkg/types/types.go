@@ -1,11 +1,15 @@
 package types
 
 import (
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
+
+	"yunion.io/x/pkg/errors"
 )
 
 type GlobalChangeLogConfig struct {
@@ -21,15 +25,77 @@ type GlobalChangeLogConfig struct {
 	Options *ChangelogConfigOptions `json:"options"`
 	// Output configure output handle options
 	Output *GlobalChangelogOutConfig `json:"output"`
+	// Discovery finds release branches of repos, the discovered ones are appended to Releases
+	Discovery *ReleaseDiscoveryConfig `json:"discovery"`
+	// KeepGoing collects failures of repos and releases instead of aborting
+	KeepGoing bool `json:"keepGoing"`
+	// Overrides loaded from `Options.OverridesFile`, shared by all repos
+	Overrides *CommitOverrides `json:"-"`
+	// NoteErrors collects malformed git notes of all repos if not nil
+	NoteErrors *CommitNoteErrors `json:"-"`
+	// RepoFilters are commit filters of each repo name
+	RepoFilters map[string]*CommitFilterConfig `json:"repoFilters"`
+	// RepoPaths are path specs of each repo name, the commits are restricted to the paths
+	RepoPaths map[string][]string `json:"repoPaths"`
+	// RepoComponents are components of each repo name
+	RepoComponents map[string][]*ComponentConfig `json:"repoComponents"`
+}
+
+// ComponentConfig maps path globs of monorepo to the component name
+type ComponentConfig struct {
+	Name string `json:"name"`
+	// Paths are globs relative to repository root, the directory matches all files in it (e.g. `pkg/compute`, `web/**`, `*.md`)
+	Paths []string `json:"paths"`
+}
+
+// CommitFilterConfig filters commits by expressions, e.g. `type in (feat,fix) && author.email !~ "bot@"`.
+// The commit is kept if it matches any of Include (all commits if empty) and none of Exclude
+type CommitFilterConfig struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// ReleaseDiscoveryConfig lists remote release branches from the cached repos
+type ReleaseDiscoveryConfig struct {
+	Repos []*Repository `json:"repos"`
+	// Latest is the count of newest release lines, all are used if it's 0
+	Latest int `json:"latest"`
+	// MinVersion is the oldest `major.minor` release line, e.g. `3.4`
+	MinVersion string `json:"minVersion"`
 }
 
 func (gConf GlobalChangeLogConfig) ToChangelogConfig(rls ReleaseChangeLogConfig, repoIdx int) *ChangelogConfig {
-	return rls.ToChangelogConfig(gConf.Bin, gConf.Options, repoIdx)
+	conf := rls.ToChangelogConfig(gConf.Bin, gConf.Options, repoIdx)
+	conf.Overrides = gConf.Overrides
+	conf.NoteErrors = gConf.NoteErrors
+	if filter, ok := gConf.RepoFilters[rls.Repos[repoIdx].Name]; ok {
+		conf.Filters = append(conf.Filters, filter)
+	}
+	if gConf.Output != nil && gConf.Output.Filter != nil {
+		conf.Filters = append(conf.Filters, gConf.Output.Filter)
+	}
+	conf.Paths = gConf.RepoPaths[rls.Repos[repoIdx].Name]
+	conf.Components = gConf.RepoComponents[rls.Repos[repoIdx].Name]
+	return conf
 }
 
 type ReleaseChangeLogConfig struct {
 	Branch string        `json:"branch"`
 	Repos  []*Repository `json:"repos"`
+	// RepoBranches are the branch names of repo names different from Branch, e.g. `release-3.10` of `release/3.10`
+	RepoBranches map[string]string `json:"repoBranches"`
+	// NextTag renders unreleased commits of repos as a draft version
+	NextTag string `json:"nextTag"`
+	// BaseRef is the start of the first version of release branch
+	BaseRef string `json:"baseRef"`
+}
+
+// RepoBranch returns the branch name of repo
+func (rConf ReleaseChangeLogConfig) RepoBranch(repoIdx int) string {
+	if branch, ok := rConf.RepoBranches[rConf.Repos[repoIdx].Name]; ok {
+		return branch
+	}
+	return rConf.Branch
 }
 
 func (rConf ReleaseChangeLogConfig) ToChangelogConfig(bin string, opts *ChangelogConfigOptions, repoIdx int) *ChangelogConfig {
@@ -37,6 +103,8 @@ func (rConf ReleaseChangeLogConfig) ToChangelogConfig(bin string, opts *Changelo
 	return &ChangelogConfig{
 		Bin:        bin,
 		WorkingDir: repo.WorkingDir,
+		Branch:     rConf.RepoBranch(repoIdx),
+		BaseRef:    rConf.BaseRef,
 		Info: &ChangelogConfigInfo{
 			RepositoryURL: repo.URL,
 		},
@@ -62,6 +130,22 @@ type ChangelogConfig struct {
 	WorkingDir string `json:"workingDir"`
 	// Path for template file. If a relative path is specified, it depends on the value of `WorkingDir`.
 	Template string
+	// Branch is the release branch of repository
+	Branch string `json:"branch"`
+	// HeadRef is the reference of unreleased commits, default is `HEAD`
+	HeadRef string `json:"headRef"`
+	// BaseRef is the start of the first version of release line, default is the fork point with the previous release line
+	BaseRef string `json:"baseRef"`
+	// Overrides rewrite or hide commits, they are loaded from `Options.OverridesFile` by the caller
+	Overrides *CommitOverrides `json:"-"`
+	// NoteErrors collects malformed git notes if not nil
+	NoteErrors *CommitNoteErrors `json:"-"`
+	// Filters of repo and output applied besides `Options.Filter`
+	Filters []*CommitFilterConfig `json:"-"`
+	// Paths restrict commits to the path specs of `git log`, e.g. `pkg/compute`
+	Paths []string `json:"paths"`
+	// Components attribute commits by the touched files, commits are split into components of version
+	Components []*ComponentConfig `json:"components"`
 
 	Info    *ChangelogConfigInfo    `json:"info"`
 	Options *ChangelogConfigOptions `json:"options"`
@@ -85,6 +169,8 @@ type ChangelogConfigOptions struct {
 	NoCaseSensitive bool `json:"noCaseSensitive"`
 	// Filter by using `Commit` properties and values. Filtering is not done by specifying an empty value
 	CommitFilters map[string][]string `json:"commitFilters"`
+	// Filter by expressions of `Commit` properties, it's applied after `CommitFilters`
+	Filter *CommitFilterConfig `json:"filter"`
 	// Property name to use for sorting `Commit` (e.g. `Scope`)
 	CommitSortBy string `json:"commitSortBy"`
 	// Property name of `Commit` to be grouped into `CommitGroup` (e.g. `Type`)
@@ -93,6 +179,11 @@ type ChangelogConfigOptions struct {
 	CommitGroupSortBy string `json:"commitGroupSortBy"`
 	// Map for `CommitGroup` title conversion
 	CommitGroupTitleMaps map[string]string `json:"commitGroupTitleMaps"`
+	// Property name of `Commit` to be grouped into `CommitGroup.SubGroups` of each group (e.g. `Scope`),
+	// the commit of list property (e.g. `Scopes`, `Components`) is put into the sub group of each value
+	CommitSubGroupBy string `json:"commitSubGroupBy"`
+	// Property name to use for sorting `CommitGroup.SubGroups`, default is `Title`
+	CommitSubGroupSortBy string `json:"commitSubGroupSortBy"`
 	// A regular expression to use for parsing the commit header
 	HeaderPattern string `json:"headerPattern"`
 	// A rule for mapping the result of `HeaderPattern` to the property of `Commit`
@@ -113,11 +204,83 @@ type ChangelogConfigOptions struct {
 	RevertPatternMaps []string `json:"revertPatternMaps"`
 	// Keyword list to find `Note`. A semicolon is a separator, like `<keyword>:` (e.g. `BREAKING CHANGE`)
 	NoteKeywords []string `json:"noteKeywords"`
+	// Path of global mailmap file, it takes precedence over the `.mailmap` in repository
+	MailmapFile string `json:"mailmapFile"`
+	// Path of yaml file to rewrite or hide commits by hash or `Change-Id`
+	OverridesFile string `json:"overridesFile"`
+	// Notes ref of per-commit overrides (e.g. `refs/notes/changelog`), the note is yaml of the override fields
+	NotesRef string `json:"notesRef"`
+	// Extract fenced ```release-note blocks of commit body into `Commit.ReleaseNote`,
+	// the commits with `NONE` release note are excluded
+	ReleaseNoteBlocks bool `json:"releaseNoteBlocks"`
+	// Collect changed files and lines of commits by `git log --numstat`, versions get the aggregate `DiffStat`
+	DiffStat bool `json:"diffStat"`
+	// Map commit type alias to canonical type (e.g. `feature: feat`), the matching is case insensitive.
+	// The keys of `CommitGroupTitleMaps` are canonical types as well
+	TypeAliases map[string]string `json:"typeAliases"`
+	// Map commit scope alias to canonical scope (e.g. `hostman: host`), the matching is case insensitive
+	ScopeAliases map[string]string `json:"scopeAliases"`
+	// Map commit `Type` to semantic version bump level (`major|minor|patch|none`), it's merged over the default
+	// `feat: minor` and `none` of `docs`, `chore`, `ci` and `test`. Commits with breaking change notes always bump major,
+	// others bump patch
+	BumpRules map[string]string `json:"bumpRules"`
+	// Regexp list of release branch names, the `major.minor` version is captured by the named group `version`
+	// or the first group (e.g. `^v(?P<version>\d+\.\d+)-stable$`), default is `release[/-](\d+\.\d+)$`
+	ReleaseBranchPatterns []string `json:"releaseBranchPatterns"`
+}
+
+func (o *ChangelogConfigOptions) Validate() error {
+	for typ, level := range o.BumpRules {
+		switch level {
+		case BumpMajor, BumpMinor, BumpPatch, BumpNone:
+		default:
+			return errors.Errorf("invalid bump level %q of type %q, choices(`%s|%s|%s|%s`)", level, typ, BumpMajor, BumpMinor, BumpPatch, BumpNone)
+		}
+	}
+
+	return nil
 }
 
+const (
+	// EmailPolicyShow renders email as `mailto:` link
+	EmailPolicyShow = "show"
+	// EmailPolicyObfuscate renders email in a form not recognized by crawlers
+	EmailPolicyObfuscate = "obfuscate"
+	// EmailPolicyProfile renders the profile link of code hosting instead of email
+	EmailPolicyProfile = "profile"
+	// EmailPolicyOmit renders name only
+	EmailPolicyOmit = "omit"
+)
+
 type GlobalChangelogOutConfig struct {
 	// Dir is output dir
 	Dir string `json:"dir"`
+	// EmailPolicy controls how emails are rendered, choices(`show|obfuscate|profile|omit`), default is `show`
+	EmailPolicy string `json:"emailPolicy"`
+	// Profiles maps email to user name of code hosting, used by `profile` email policy
+	Profiles map[string]string `json:"profiles"`
+	// RedactPatterns are regular expressions, the matched text of commit subject and body is redacted
+	RedactPatterns []string `json:"redactPatterns"`
+	// NotesDir contains hand-written release notes `<branch>/<version>.md` merged into versions
+	NotesDir string `json:"notesDir"`
+	// Filter drops commits from the output, it's applied besides filters of options and repo
+	Filter *CommitFilterConfig `json:"filter"`
+}
+
+func (c *GlobalChangelogOutConfig) Validate() error {
+	switch c.EmailPolicy {
+	case "", EmailPolicyShow, EmailPolicyObfuscate, EmailPolicyProfile, EmailPolicyOmit:
+	default:
+		return errors.Errorf("invalid email policy %q", c.EmailPolicy)
+	}
+
+	for _, pattern := range c.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrapf(err, "invalid redact pattern %q", pattern)
+		}
+	}
+
+	return nil
 }
 
 type Commit struct {
@@ -133,17 +296,80 @@ type Commit struct {
 	Notes  []*CommitNote `json:"notes"`
 	// Name of the user included in the commit header or body
 	Mentions []string `json:"mentions"`
+	// Identities from `Co-authored-by` trailers
+	CoAuthors []*CommitContributor `json:"coAuthors"`
+	// Identities from `Signed-off-by` trailers
+	SignedOffBy []*CommitContributor `json:"signedOffBy"`
+	// Identities from `Reviewed-by` trailers
+	ReviewedBy []*CommitContributor `json:"reviewedBy"`
+	// Gerrit `Change-Id` trailer
+	ChangeID string `json:"changeId"`
+	// Content of fenced ```release-note block in body, it's preferred over `Subject` when rendering
+	ReleaseNote string `json:"releaseNote"`
+	// Files touched by the commit, it's only parsed if components are configured or `DiffStat` option is set
+	Files []string `json:"files"`
+	// FileStats are changed lines of each file, it's only parsed if `DiffStat` option is set
+	FileStats  []*CommitFileStat `json:"fileStats"`
+	Insertions int               `json:"insertions"`
+	Deletions  int               `json:"deletions"`
+	// Components matched by `Files`
+	Components []string `json:"components"`
 	// (e.g. `feat(core): add new feature`)
 	Header string `json:"header"`
 	// (e.g. `feat`)
 	Type string `json:"type"`
 	// (e.g. `core`)
 	Scope string `json:"scope"`
+	// Scopes split from comma separated `Scope` (e.g. `region,host`)
+	Scopes []string `json:"scopes"`
 	// (e.g. `add new feature`)
 	Subject string `json:"subject"`
 	Body    string `json:"body"`
 }
 
+// CommitFileStat is the changed lines of file from `git log --numstat`
+type CommitFileStat struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	// Binary file has no changed lines
+	Binary bool `json:"binary"`
+}
+
+// DiffStat is the aggregate changes of commits
+type DiffStat struct {
+	Files      int `json:"files"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// NewDiffStat sums changed lines of commits, the files changed by multiple commits are counted once
+func NewDiffStat(commits []*Commit) *DiffStat {
+	stat := new(DiffStat)
+	files := make(map[string]bool)
+	for _, commit := range commits {
+		stat.Insertions += commit.Insertions
+		stat.Deletions += commit.Deletions
+		for _, file := range commit.Files {
+			files[file] = true
+		}
+	}
+	stat.Files = len(files)
+	return stat
+}
+
+// Add sums the changes of another repository
+func (s *DiffStat) Add(o *DiffStat) {
+	s.Files += o.Files
+	s.Insertions += o.Insertions
+	s.Deletions += o.Deletions
+}
+
+// String renders the stat, e.g. `+1200/-300 across 45 files`
+func (s *DiffStat) String() string {
+	return fmt.Sprintf("+%d/-%d across %d files", s.Insertions, s.Deletions, s.Files)
+}
+
 type CommitHash struct {
 	Long  string `json:"long"`
 	Short string `json:"short"`
@@ -153,6 +379,8 @@ type CommitAuthor struct {
 	Name  string    `json:"name"`
 	Email string    `json:"email"`
 	Date  time.Time `json:"date"`
+	// Profile link rendered instead of email if not empty
+	URL string `json:"url"`
 }
 
 type CommitCommitter struct {
@@ -161,6 +389,97 @@ type CommitCommitter struct {
 	Date  time.Time `json:"date"`
 }
 
+// CommitContributor is an identity parsed from a commit trailer
+// (e.g. `Co-authored-by: Name <mail@example.com>`)
+type CommitContributor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Profile link rendered instead of email if not empty
+	URL string `json:"url"`
+}
+
+// Key returns the identity used for deduplication
+func (c *CommitContributor) Key() string {
+	if c.URL != "" {
+		return strings.ToLower(c.URL)
+	}
+	if c.Email != "" {
+		return strings.ToLower(c.Email)
+	}
+	return strings.ToLower(c.Name)
+}
+
+// Contributors returns the commit author followed by the co-authors, without duplicates
+func (c *Commit) Contributors() []*CommitContributor {
+	ret := []*CommitContributor{}
+	seen := make(map[string]bool)
+
+	add := func(ct *CommitContributor) {
+		if seen[ct.Key()] {
+			return
+		}
+		seen[ct.Key()] = true
+		ret = append(ret, ct)
+	}
+
+	if c.Author != nil {
+		add(&CommitContributor{
+			Name:  c.Author.Name,
+			Email: c.Author.Email,
+			URL:   c.Author.URL,
+		})
+	}
+	for _, ct := range c.CoAuthors {
+		add(ct)
+	}
+
+	return ret
+}
+
+// Reviewers returns the identities of `Reviewed-by` trailers who are not the author or co-authors
+func (c *Commit) Reviewers() []*CommitContributor {
+	ret := []*CommitContributor{}
+	seen := make(map[string]bool)
+	for _, ct := range c.Contributors() {
+		seen[ct.Key()] = true
+	}
+
+	for _, ct := range c.ReviewedBy {
+		if seen[ct.Key()] {
+			continue
+		}
+		seen[ct.Key()] = true
+		ret = append(ret, ct)
+	}
+
+	return ret
+}
+
+// Contributor is a commit author, co-author or reviewer with the commits count in a version
+type Contributor struct {
+	*CommitContributor
+	// Commits count authored or co-authored by the contributor
+	Commits int `json:"commits"`
+	// Reviews count of commits reviewed by the contributor
+	Reviews int `json:"reviews"`
+	// FirstTime is true if none of the commits reachable from the previous tag is authored by the contributor,
+	// it's always false for the reviewer who authored no commits
+	FirstTime bool `json:"firstTime"`
+}
+
+// SortContributors sorts contributors by commits count, then by reviews count and name
+func SortContributors(contributors []*Contributor) {
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].Commits != contributors[j].Commits {
+			return contributors[i].Commits > contributors[j].Commits
+		}
+		if contributors[i].Reviews != contributors[j].Reviews {
+			return contributors[i].Reviews > contributors[j].Reviews
+		}
+		return strings.ToLower(contributors[i].Name) < strings.ToLower(contributors[j].Name)
+	})
+}
+
 // CommitMerge info
 type CommitMerge struct {
 	Ref    string
@@ -202,6 +521,8 @@ type CommitGroup struct {
 	// Conversion by `commitGroupTitleMaps` option, or title converted in title case (e.g. `Build`)
 	Title   string
 	Commits []*Commit
+	// Commits grouped by `CommitSubGroupBy` option, the title is the raw value
+	SubGroups []*CommitGroup
 }
 
 // RelateTag is sibling tag data of `Tag`.
@@ -213,6 +534,15 @@ type RelateTag struct {
 	Date    time.Time
 }
 
+const (
+	// TagSignatureUnsigned is annotated tag without signature
+	TagSignatureUnsigned = "unsigned"
+	// TagSignatureGood is verified by `git verify-tag`
+	TagSignatureGood = "good"
+	// TagSignatureUnverified is bad signature or signed by unknown key
+	TagSignatureUnverified = "unverified"
+)
+
 // Tag is data of git-tag
 type Tag struct {
 	Name     string
@@ -221,6 +551,12 @@ type Tag struct {
 	Next     *RelateTag
 	Previous *RelateTag
 	Version  *semver.Version
+	// Body is the message of annotated tag without subject and signature
+	Body string
+	// Tagger is the identity of annotated tag
+	Tagger *CommitContributor
+	// Signature is the status of annotated tag signature, empty for lightweight tag
+	Signature string
 }
 
 // Version is a tag-separeted datset to be included in CHANGELOG
@@ -231,6 +567,10 @@ type Version struct {
 	MergeCommits  []*Commit          `json:"mergeCommits"`
 	RevertCommits []*Commit          `json:"revertCommits"`
 	NoteGroups    []*CommitNoteGroup `json:"noteGroups"`
+	Contributors  []*Contributor     `json:"contributors"`
+	Components    []*ComponentGroup  `json:"components"`
+	// DiffStat is nil if `DiffStat` option is not set
+	DiffStat *DiffStat `json:"diffStat"`
 }
 
 // Unreleased is unreleased commit dataset
@@ -240,6 +580,35 @@ type Unreleased struct {
 	MergeCommits  []*Commit          `json:"mergeCommits"`
 	RevertCommits []*Commit          `json:"revertCommits"`
 	NoteGroups    []*CommitNoteGroup `json:"noteGroups"`
+	Contributors  []*Contributor     `json:"contributors"`
+	Components    []*ComponentGroup  `json:"components"`
+	DiffStat      *DiffStat          `json:"diffStat"`
+}
+
+// ComponentOthers is the component of commits not matched by any component
+const ComponentOthers = "Others"
+
+// ComponentGroup is the commits of version attributed to a component, a commit touched
+// multiple components is in each of them
+type ComponentGroup struct {
+	Name         string         `json:"name"`
+	CommitGroups []*CommitGroup `json:"commitGroups"`
+	Commits      []*Commit      `json:"commits"`
+}
+
+// ToVersion treats unreleased commits as the version of tag
+func (u *Unreleased) ToVersion(tag *Tag) *Version {
+	return &Version{
+		Tag:           tag,
+		CommitGroups:  u.CommitGroups,
+		Commits:       u.Commits,
+		MergeCommits:  u.MergeCommits,
+		RevertCommits: u.RevertCommits,
+		NoteGroups:    u.NoteGroups,
+		Contributors:  u.Contributors,
+		Components:    u.Components,
+		DiffStat:      u.DiffStat,
+	}
 }
 
 // RenderData is the data passed to the template
@@ -251,12 +620,28 @@ type RenderData struct {
 
 type GlobalChangeLogResult struct {
 	Releases []*ReleaseChangeLogResult `json:"releases"`
+	// Failures of repos skipped by `KeepGoing`
+	Failures []*RepoFailure `json:"failures"`
 }
 
 type ReleaseChangeLogResult struct {
 	Branch string                 `json:"branch"`
 	Weight int                    `json:"-"`
 	Repos  []*RepoChangelogResult `json:"repos"`
+	// NextTag is the draft version of unreleased commits
+	NextTag string `json:"nextTag"`
+	// Failures of repos skipped by `KeepGoing`
+	Failures []*RepoFailure `json:"failures"`
+}
+
+// RepoFailure is the error of generating changelog of a repo on release branch,
+// Repo is empty if the whole release failed
+type RepoFailure struct {
+	Branch string `json:"branch"`
+	Repo   string `json:"repo"`
+	Error  string `json:"error"`
+	// Versions of repo tags on the branch, the versions lack the repo. It's nil if the tags can't be read
+	Versions []string `json:"versions"`
 }
 
 // RepoChangelogResult contains repo version commits
@@ -268,12 +653,16 @@ type RepoChangelogResult struct {
 
 type GlobalRenderData struct {
 	Releases []*ReleaseRenderData
+	// Failures of repos and releases skipped by `KeepGoing`
+	Failures []*RepoFailure
 }
 
 type ReleaseRenderData struct {
 	Branch   string
 	Weight   int
 	Versions []*GlobalVersionRenderData
+	// Failures of repos skipped by `KeepGoing`
+	Failures []*RepoFailure
 }
 
 type GlobalVersionRenderData struct {
@@ -281,6 +670,172 @@ type GlobalVersionRenderData struct {
 	Date    time.Time
 	Weight  int
 	Repos   []*RepoVersionRenderData
+	// Contributors of all repos
+	Contributors []*Contributor
+	// Draft is true if the version is not released yet
+	Draft bool
+	// MissingRepos are names of repos failed to generate the version, the draft version lacks all failed repos
+	MissingRepos []string
+	// Highlights of release note and annotated tag messages of all repos
+	Highlights []*Highlight
+	// KnownIssues of release note
+	KnownIssues []string
+	// UpgradeSteps of release note
+	UpgradeSteps []string
+	// Notes is the markdown content of release note
+	Notes string
+	// DiffStat of all repos, it's nil if no repo has diff stat
+	DiffStat *DiffStat
+}
+
+// ReleaseNote is hand-written notes of version, the front matter of markdown file is
+//
+//	highlights: ["..."]
+//	knownIssues: ["..."]
+//	upgradeSteps: ["..."]
+type ReleaseNote struct {
+	Highlights   []string `json:"highlights"`
+	KnownIssues  []string `json:"knownIssues"`
+	UpgradeSteps []string `json:"upgradeSteps"`
+	// Content is the markdown after front matter
+	Content string `json:"-"`
+}
+
+// MergeReleaseNote puts highlights of release note before the ones of tag messages
+func (data *GlobalVersionRenderData) MergeReleaseNote(note *ReleaseNote) {
+	highlights := make([]*Highlight, 0, len(note.Highlights)+len(data.Highlights))
+	for _, text := range note.Highlights {
+		highlights = append(highlights, &Highlight{Text: text})
+	}
+	data.Highlights = append(highlights, data.Highlights...)
+	data.KnownIssues = append(data.KnownIssues, note.KnownIssues...)
+	data.UpgradeSteps = append(data.UpgradeSteps, note.UpgradeSteps...)
+	data.Notes = note.Content
+}
+
+var (
+	// list item of markdown, e.g. `- foo`, `* foo` or `1. foo`
+	reHighlightListItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+)
+
+// Highlight is an item of annotated tag message
+type Highlight struct {
+	Text string
+	// Repos are names of repos having the highlight
+	Repos []string
+}
+
+// MergeHighlights collects the items of tag messages of repos, the same items are merged
+func (data *GlobalVersionRenderData) MergeHighlights() {
+	data.Highlights = make([]*Highlight, 0)
+	index := make(map[string]*Highlight)
+
+	for _, repo := range data.Repos {
+		if repo.Version == nil || repo.Tag == nil {
+			continue
+		}
+		for _, text := range ParseHighlights(repo.Tag.Body) {
+			key := strings.ToLower(text)
+			highlight, ok := index[key]
+			if !ok {
+				highlight = &Highlight{
+					Text: text,
+				}
+				index[key] = highlight
+				data.Highlights = append(data.Highlights, highlight)
+			}
+			highlight.Repos = append(highlight.Repos, repo.Repo.Name)
+		}
+	}
+}
+
+// ParseHighlights splits message to items, each list item is an item and the prose is ignored.
+// Each paragraph is an item if message has no list
+func ParseHighlights(message string) []string {
+	var (
+		listItems  []string
+		paragraphs []string
+		current    []string
+		inList     bool
+	)
+
+	flush := func() {
+		if len(current) > 0 {
+			if inList {
+				listItems = append(listItems, strings.Join(current, " "))
+			} else {
+				paragraphs = append(paragraphs, strings.Join(current, " "))
+			}
+			current = nil
+		}
+		inList = false
+	}
+
+	for _, line := range strings.Split(message, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		if item := reHighlightListItem.FindStringSubmatch(line); item != nil {
+			flush()
+			inList = true
+			trimmed = strings.TrimSpace(item[1])
+		}
+		current = append(current, trimmed)
+	}
+	flush()
+
+	if len(listItems) > 0 {
+		return listItems
+	}
+	return paragraphs
+}
+
+// MergeContributors sums up contributors of repos, a contributor is first-time
+// only if it is first-time in every repo it contributes to
+func (data *GlobalVersionRenderData) MergeContributors() {
+	data.Contributors = make([]*Contributor, 0)
+	index := make(map[string]*Contributor)
+
+	for _, repo := range data.Repos {
+		for _, c := range repo.Contributors {
+			merged, ok := index[c.Key()]
+			if !ok {
+				merged = &Contributor{
+					CommitContributor: c.CommitContributor,
+					FirstTime:         true,
+				}
+				index[c.Key()] = merged
+				data.Contributors = append(data.Contributors, merged)
+			}
+			merged.Commits += c.Commits
+			merged.Reviews += c.Reviews
+			if c.Commits > 0 {
+				merged.FirstTime = merged.FirstTime && c.FirstTime
+			}
+		}
+	}
+
+	for _, c := range data.Contributors {
+		c.FirstTime = c.FirstTime && c.Commits > 0
+	}
+
+	SortContributors(data.Contributors)
+}
+
+// MergeDiffStat sums diff stats of repos
+func (data *GlobalVersionRenderData) MergeDiffStat() {
+	data.DiffStat = nil
+	for _, repo := range data.Repos {
+		if repo.Version == nil || repo.DiffStat == nil {
+			continue
+		}
+		if data.DiffStat == nil {
+			data.DiffStat = new(DiffStat)
+		}
+		data.DiffStat.Add(repo.DiffStat)
+	}
 }
 
 func (data *GlobalVersionRenderData) Sort() {